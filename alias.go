@@ -0,0 +1,81 @@
+package sevseg
+
+// wideLetters are the two-digit approximations of the letters which can't be
+// shown on a single digit, used with Config.ExpandWideLetters.
+var wideLetters = []glyphAlias{
+	{'M', []uint8{0b00110001, 0b00000111}},
+	{'W', []uint8{0b00111000, 0b00001110}},
+}
+
+// glyphAlias replaces the glyph of a character with one or more patterns.
+type glyphAlias struct {
+	char     byte
+	patterns []uint8
+}
+
+// AliasChar makes the display render the character from like the character
+// to, e.g. AliasChar('V', 'U'), without patching the font table. Aliases
+// apply to all text rendering and ignore the case of letters like the font.
+//
+// Returns ErrUnsupportedChar if to is not supported.
+func (s *SevSeg) AliasChar(from, to byte) error {
+	s.lock()
+	defer s.unlock()
+
+	return s.format.AliasChar(from, to)
+}
+
+// AliasChar is SevSeg.AliasChar for the formatter.
+func (f *Formatter) AliasChar(from, to byte) error {
+	patterns, ok := f.appendGlyph(nil, to)
+	if !ok {
+		return ErrUnsupportedChar
+	}
+
+	f.AliasGlyph(from, patterns...)
+
+	return nil
+}
+
+// AliasGlyph makes the display render the character from with the given
+// segment patterns, e.g. 'V' as 'U' with a decimal point, or 'M' expanded to
+// two digits "nn" for scrolling text:
+//
+//	display.AliasGlyph('M', 0b01010100, 0b01010100)
+//
+// The patterns are in reading order, i.e. the first pattern is the left most
+// digit. Without patterns, the alias of the character is removed.
+func (s *SevSeg) AliasGlyph(from byte, patterns ...uint8) {
+	s.lock()
+	defer s.unlock()
+
+	s.format.AliasGlyph(from, patterns...)
+}
+
+// AliasGlyph is SevSeg.AliasGlyph for the formatter.
+func (f *Formatter) AliasGlyph(from byte, patterns ...uint8) {
+	from = upperCase(from)
+
+	for i := range f.aliases {
+		if f.aliases[i].char == from {
+			f.aliases = append(f.aliases[:i], f.aliases[i+1:]...)
+			break
+		}
+	}
+
+	if len(patterns) > 0 {
+		f.aliases = append(f.aliases, glyphAlias{
+			char:     from,
+			patterns: append([]uint8(nil), patterns...),
+		})
+	}
+}
+
+// upperCase converts lower-case letters to upper-case.
+func upperCase(char byte) byte {
+	if char >= 'a' && char <= 'z' {
+		return char - 'a' + 'A'
+	}
+
+	return char
+}
@@ -0,0 +1,77 @@
+package sevseg
+
+// Content is a snapshot of the display content, one segment pattern per
+// digit. Like the patterns of SetSegment, the first element is the right most
+// digit.
+type Content []uint8
+
+// Snapshot returns a copy of the content currently set on the display. It can
+// be used to build the secondary content for AlternateWith, e.g.:
+//
+//	display.SetText("*C")
+//	unit := display.Snapshot()
+//	display.SetNumberFloat(23.4, 1)
+//	display.AlternateWith(unit, 500)
+func (s *SevSeg) Snapshot() Content {
+	s.lock()
+	defer s.unlock()
+
+	content := make(Content, len(s.updatedDisplay))
+	copy(content, s.updatedDisplay)
+
+	return content
+}
+
+// AlternateWith makes the display flip between the current content and the
+// secondary content every periodTicks ticks of the effects clock, i.e. full
+// frames of Refresh. This is useful to show a value and its unit or label on
+// narrow displays.
+//
+// The current content can still be updated while alternating. If the
+// secondary content is shorter than the display, the remaining digits (on the
+// left) are cleared.
+func (s *SevSeg) AlternateWith(secondary Content, periodTicks uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if len(secondary) > len(s.digitPins) {
+		return ErrTooManyDigits
+	}
+
+	if periodTicks == 0 {
+		return ErrInvalidArgument
+	}
+
+	// Replaced while it isn't shown, see ClearWarning
+	s.showAlternate = false
+
+	content := make([]uint8, len(s.digitPins))
+	copy(content, secondary)
+	s.alternateContent = content
+
+	s.alternatePeriod = periodTicks
+	s.alternateNext = s.deadline(periodTicks)
+
+	return nil
+}
+
+// StopAlternate stops alternating and shows the current content again.
+func (s *SevSeg) StopAlternate() {
+	s.lock()
+	defer s.unlock()
+
+	// The content must stay until it isn't shown anymore, see ClearWarning
+	s.showAlternate = false
+	s.alternateContent = nil
+}
+
+// advanceAlternate advances the alternation by one tick of the effects clock.
+func (s *SevSeg) advanceAlternate() {
+	if s.alternateContent == nil {
+		return
+	}
+
+	if s.expired(&s.alternateNext, s.alternatePeriod) {
+		s.showAlternate = !s.showAlternate
+	}
+}
@@ -0,0 +1,97 @@
+package sevseg
+
+// Spinner is an animation of a single segment rotating around the digit, e.g.
+// for an activity indicator with SetAnimatedDigit.
+var Spinner = []uint8{
+	0b00000001, // Segment A
+	0b00000010, // Segment B
+	0b00000100, // Segment C
+	0b00001000, // Segment D
+	0b00010000, // Segment E
+	0b00100000, // Segment F
+}
+
+type animatedDigit struct {
+	position      uint8
+	frames        []uint8
+	framesPerStep uint16
+	next          uint32 // Clock of the next step
+	current       uint8
+}
+
+// SetAnimatedDigit shows a multi-frame glyph on the digit at position (zero
+// indexed from the right) while the rest of the content stays static, e.g. the
+// Spinner as an activity indicator. The glyph advances to its next frame every
+// framesPerStep ticks of the effects clock, i.e. full frames of Refresh, and
+// replaces the content of that digit until ClearAnimatedDigit is called. The
+// frames are referenced, not copied.
+func (s *SevSeg) SetAnimatedDigit(position uint8, frames []uint8, framesPerStep uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if position >= uint8(len(s.digitPins)) {
+		return ErrOutOfRange
+	}
+
+	if len(frames) == 0 || len(frames) > 255 || framesPerStep == 0 {
+		return ErrInvalidArgument
+	}
+
+	animation := animatedDigit{
+		position:      position,
+		frames:        frames,
+		framesPerStep: framesPerStep,
+		next:          s.deadline(framesPerStep),
+	}
+
+	for i := range s.animations {
+		if s.animations[i].position == position {
+			s.animations[i] = animation
+			return nil
+		}
+	}
+
+	s.animations = append(s.animations, animation)
+
+	return nil
+}
+
+// ClearAnimatedDigit stops the animation on the digit at position and shows
+// the content of the digit again.
+func (s *SevSeg) ClearAnimatedDigit(position uint8) {
+	s.lock()
+	defer s.unlock()
+
+	for i := range s.animations {
+		if s.animations[i].position == position {
+			s.animations = append(s.animations[:i], s.animations[i+1:]...)
+			return
+		}
+	}
+}
+
+// advanceAnimations advances the animated digits by one tick of the effects
+// clock.
+func (s *SevSeg) advanceAnimations() {
+	for i := range s.animations {
+		animation := &s.animations[i]
+
+		if s.expired(&animation.next, animation.framesPerStep) {
+			animation.current = uint8((int(animation.current) + 1) % len(animation.frames))
+		}
+	}
+}
+
+// animatedPattern returns the current frame of the animation on the given
+// digit, if there is one.
+func (s *SevSeg) animatedPattern(digit uint8) (uint8, bool) {
+	for _, animation := range s.animations {
+		// The timer interrupt may see a replaced animation with the frame
+		// index of the previous one, see framebuffer.go
+		if animation.position == digit && len(animation.frames) > 0 {
+			return animation.frames[int(animation.current)%len(animation.frames)], true
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,172 @@
+package sevseg
+
+import "time"
+
+// RefreshTimer is a periodic hardware timer which can call Refresh from its
+// interrupt, e.g. a TC on a SAMD21 or a TIMER on an nRF52. The TinyGo machine
+// package has no portable timer API, so it's implemented by the application
+// for its board.
+type RefreshTimer interface {
+	// Start calls callback rate times per second from the timer interrupt
+	// until Stop is called.
+	Start(rate uint32, callback func()) error
+	Stop()
+}
+
+// StartAutoRefresh calls Refresh rate times per second in the background, so
+// blocking code in the main loop doesn't cause flicker. A Config.RefreshTimer
+// drives it from a timer interrupt; otherwise a goroutine is used, which on
+// TinyGo only runs while the main loop sleeps or waits on a channel. A running
+// auto refresh is restarted with the new rate.
+//
+// The timer interrupt only multiplexes the digits. The timed effects, e.g.
+// AutoScroll, PlayFrames or SetCrossfade, and the SetMirror, StreamFrames and
+// LogDriver callbacks run in a goroutine instead, so they never run in
+// interrupt context; on TinyGo they only advance while the main loop sleeps
+// or waits on a channel.
+//
+// Content may be set while the auto refresh is running, it's shown from the
+// start of the next frame. Use Begin and End to show several updates at once.
+// The goroutine and the methods of the display exclude each other, while the
+// timer interrupt relies on the content being staged for the next frame. The
+// methods are meant to be called from a single goroutine, and the callbacks,
+// e.g. of SetMirror or SetOrientationProvider, must not call them, since they
+// run while the display is locked.
+// Displays with a Controller or pins on an expander (PCF8574, MCP23017)
+// always use the goroutine, since their bus transfers can't run in an
+// interrupt.
+//
+// Returns ErrInvalidArgument if rate is 0 or above one billion, or the error
+// of RefreshTimer.Start.
+func (s *SevSeg) StartAutoRefresh(rate uint32) error {
+	s.lock()
+	defer s.unlock()
+
+	if rate == 0 || rate > uint32(time.Second) {
+		return ErrInvalidArgument
+	}
+
+	s.StopAutoRefresh()
+
+	interval := time.Second / time.Duration(rate)
+	s.autoRefreshStop = make(chan struct{})
+	s.autoRefreshDone = make(chan struct{})
+
+	if s.refreshTimer != nil && s.backend == nil && len(s.banks) == 0 {
+		// Set before the first interrupt, which may fire within Start
+		s.timerRunning = true
+
+		if err := s.refreshTimer.Start(rate, s.autoRefresh); err != nil {
+			s.timerRunning = false
+			s.autoRefreshStop = nil
+			s.autoRefreshDone = nil
+
+			return err
+		}
+
+		// The effects clock ticks about once per frame
+		go s.effectsLoop(interval*time.Duration(len(s.digitPins)), s.autoRefreshStop, s.autoRefreshDone)
+
+		return nil
+	}
+
+	go s.autoRefreshLoop(interval, s.autoRefreshStop, s.autoRefreshDone)
+
+	return nil
+}
+
+// StopAutoRefresh stops the auto refresh started by StartAutoRefresh and
+// turns off the digit and segment pins, so no digit stays lit at full
+// current. The content is kept and shown again by the next Refresh. It does
+// nothing if no auto refresh is running.
+func (s *SevSeg) StopAutoRefresh() {
+	s.lock()
+	defer s.unlock()
+
+	if s.autoRefreshStop == nil {
+		return
+	}
+
+	if s.timerRunning {
+		s.refreshTimer.Stop()
+		s.timerRunning = false
+	}
+
+	// The goroutine may be waiting for the lock to finish its step
+	close(s.autoRefreshStop)
+	s.mu.Unlock()
+	<-s.autoRefreshDone
+	s.mu.Lock()
+
+	s.autoRefreshStop = nil
+	s.autoRefreshDone = nil
+	s.runPendingTicks()
+
+	if s.backend == nil {
+		s.clearDigitPins()
+		s.clearSegmentPins()
+		s.flushBanks()
+	}
+}
+
+// autoRefresh is the callback of the RefreshTimer. The interrupt can't wait
+// for the lock, the content it reads is staged instead.
+func (s *SevSeg) autoRefresh() {
+	s.refresh()
+}
+
+// autoRefreshLoop calls Refresh every interval until stop is closed, then
+// closes done.
+func (s *SevSeg) autoRefreshLoop(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.refresh()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// effectsLoop runs the effects clock ticks counted by the timer interrupt
+// every interval until stop is closed, then closes done.
+func (s *SevSeg) effectsLoop(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	defer close(done)
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.runPendingTicks()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// lock locks the display for an API call. Nested calls, e.g. SetNumberFloat
+// calling SetNumberWithDecimal, only lock it once.
+func (s *SevSeg) lock() {
+	if s.lockDepth == 0 {
+		s.mu.Lock()
+	}
+
+	s.lockDepth++
+}
+
+// unlock unlocks the display at the end of the outermost API call.
+func (s *SevSeg) unlock() {
+	s.lockDepth--
+	if s.lockDepth == 0 {
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,110 @@
+//go:build !tinygo
+
+package sevseg
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeTimer is a RefreshTimer whose interrupt is raised by the test.
+type fakeTimer struct {
+	callback func()
+}
+
+func (t *fakeTimer) Start(rate uint32, callback func()) error {
+	t.callback = callback
+	return nil
+}
+
+func (t *fakeTimer) Stop() {
+	t.callback = nil
+}
+
+func TestEffectsOutsideTimerInterrupt(t *testing.T) {
+	timer := &fakeTimer{}
+	s, err := NewSevSeg(Config{
+		DigitPins:    SimulatedPins(4),
+		SegmentPins:  SimulatedPins(8),
+		RefreshTimer: timer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streamed := 0
+	s.StreamFrames(func([]uint8) { streamed++ })
+
+	if err := s.PlayFrames([][]uint8{{0b0000110}, {0b1011011}}, 1); err != nil {
+		t.Fatal(err)
+	}
+	streamed = 0
+
+	// One tick per second, the effects goroutine doesn't run during the test
+	if err := s.StartAutoRefresh(1); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 4 * 10 {
+		timer.callback()
+	}
+
+	if streamed != 0 {
+		t.Fatalf("%d frames committed in the timer interrupt, want 0", streamed)
+	}
+
+	if ticks := s.pendingTicks.Load(); ticks == 0 {
+		t.Fatal("timer interrupt didn't count the effects clock ticks")
+	}
+
+	s.StopAutoRefresh()
+
+	if streamed == 0 {
+		t.Fatal("pending effects clock ticks not run on StopAutoRefresh")
+	}
+
+	if ticks := s.pendingTicks.Load(); ticks != 0 {
+		t.Fatalf("%d effects clock ticks left after StopAutoRefresh", ticks)
+	}
+}
+
+// TestSettersDuringAutoRefresh is meant to be run with -race: the auto
+// refresh goroutine refreshes the display and advances the effects while the
+// content is changed.
+func TestSettersDuringAutoRefresh(t *testing.T) {
+	s, err := NewSevSeg(Config{
+		DigitPins:   SimulatedPins(4),
+		SegmentPins: SimulatedPins(8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.StartAutoRefresh(20000); err != nil {
+		t.Fatal(err)
+	}
+	defer s.StopAutoRefresh()
+
+	if err := s.AutoScroll(1, ScrollLinear); err != nil {
+		t.Fatal(err)
+	}
+
+	// Several ticks of the goroutine, whose timer may be coarse on the host
+	deadline := time.Now().Add(50 * time.Millisecond)
+	for i := int32(0); time.Now().Before(deadline); i++ {
+		if err := s.SetNumber(i % 10000); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.SetText("SCROLLING"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := s.ShowWarning("ERR", 1); err != nil {
+			t.Fatal(err)
+		}
+
+		s.ClearWarning()
+		s.SetBrightness(uint8(i % 101))
+	}
+}
@@ -0,0 +1,127 @@
+package sevseg
+
+import "time"
+
+type scrollEasing uint8
+
+// ScrollLinear scrolls the text at a constant speed. ScrollEaseInOut starts
+// slow, speeds up in the middle of the text and slows down again towards its
+// end, which makes long messages easier to read.
+const (
+	ScrollLinear scrollEasing = iota
+	ScrollEaseInOut
+)
+
+// scrollEaseFactor is how many times longer the first and the last step of
+// ScrollEaseInOut take than a step in the middle of the text.
+const scrollEaseFactor = 3
+
+// AutoScroll scrolls the text set with SetText or SetTextPatterns to the left
+// by one digit every ticksPerStep ticks of the effects clock, i.e. full frames
+// of Refresh, so the main loop doesn't have to call ScrollTextLeft. The setting
+// applies to every following text, texts fitting the display don't scroll. With
+// ScrollEaseInOut, ticksPerStep is the interval in the middle of the text.
+//
+// Returns ErrInvalidArgument if ticksPerStep is 0. Use StopAutoScroll to
+// stop scrolling.
+func (s *SevSeg) AutoScroll(ticksPerStep uint16, easing scrollEasing) error {
+	s.lock()
+	defer s.unlock()
+
+	if ticksPerStep == 0 || easing > ScrollEaseInOut {
+		return ErrInvalidArgument
+	}
+
+	s.scrollPeriod = ticksPerStep
+	s.scrollStep = 0
+	s.scrollEasing = easing
+	s.scheduleScroll()
+
+	return nil
+}
+
+// AutoScrollEvery is AutoScroll with the interval between steps given as a
+// time instead of ticks, measured with the system clock. The scrolling speed
+// then stays constant even if the rate of Refresh calls fluctuates, e.g.
+// because the main loop does varying work. The steps still happen at the
+// start of a frame, so the interval should be several frames long. If Refresh
+// isn't called for longer than the interval, the text continues from where it
+// was instead of skipping the missed steps.
+//
+// Returns ErrInvalidArgument if interval isn't positive. Use StopAutoScroll
+// to stop scrolling.
+func (s *SevSeg) AutoScrollEvery(interval time.Duration, easing scrollEasing) error {
+	s.lock()
+	defer s.unlock()
+
+	if interval <= 0 || easing > ScrollEaseInOut {
+		return ErrInvalidArgument
+	}
+
+	s.scrollPeriod = 0
+	s.scrollStep = interval
+	s.scrollEasing = easing
+	s.scheduleScroll()
+
+	return nil
+}
+
+// StopAutoScroll stops scrolling automatically and keeps the text at its
+// current position.
+func (s *SevSeg) StopAutoScroll() {
+	s.lock()
+	defer s.unlock()
+
+	s.scrollPeriod = 0
+	s.scrollStep = 0
+}
+
+// advanceAutoScroll advances the automatic scrolling by one tick of the effects
+// clock.
+func (s *SevSeg) advanceAutoScroll() {
+	if s.scrollPeriod == 0 && s.scrollStep == 0 || s.contentType != ContentText {
+		return
+	}
+
+	if s.scrollStep > 0 {
+		if time.Now().Before(s.scrollDue) {
+			return
+		}
+	} else if !s.expired(&s.scrollNext, 0) {
+		return
+	}
+
+	s.scrollTextLeft()
+	s.scheduleScroll()
+}
+
+// scheduleScroll sets the time of the next step of the automatic scrolling.
+func (s *SevSeg) scheduleScroll() {
+	if s.scrollStep > 0 {
+		s.scrollDue = time.Now().Add(time.Duration(s.easedInterval(uint64(s.scrollStep))))
+		return
+	}
+
+	s.scrollNext = s.deadline(uint16(min(s.easedInterval(uint64(s.scrollPeriod)), 0xffff)))
+}
+
+// easedInterval returns the interval until the next step, given the interval
+// of a step at full speed in ticks or as a time.
+//
+// With ScrollEaseInOut, the interval ramps down linearly from
+// scrollEaseFactor times the period over the first digits of the text and up
+// again over the last digits, including the blanks following the text.
+func (s *SevSeg) easedInterval(period uint64) uint64 {
+	if s.scrollEasing != ScrollEaseInOut {
+		return period
+	}
+
+	length := s.textLength()
+	ramp := len(s.digitPins)
+	distance := min(s.scrollPosition, length-1-s.scrollPosition)
+	if distance >= ramp {
+		return period
+	}
+
+	return period + period*(scrollEaseFactor-1)*uint64(ramp-distance)/uint64(ramp)
+}
@@ -0,0 +1,61 @@
+package sevseg
+
+// Begin starts a batch of updates, e.g. SetNumber followed by SetColon and
+// SetAnnotation, which is shown at once by End. Until then,
+// Refresh keeps showing the frame from the time of Begin, so no intermediate
+// frame with only part of the new content is visible. The history, the mirror
+// driver and a controller only receive the final frame.
+//
+// Only the segment patterns are held back; the brightness, the colors and On,
+// Off and Toggle take effect immediately. Calls of Begin and End may be
+// nested, the batch is shown by the outermost End.
+func (s *SevSeg) Begin() {
+	s.lock()
+	defer s.unlock()
+
+	if s.batchDepth == 0 {
+		if s.batchFrame == nil {
+			s.batchFrame = make([]uint8, s.slotCount())
+		}
+
+		for i := range s.batchFrame {
+			s.batchFrame[i] = s.slotPattern(uint8(i))
+		}
+
+		s.batchChanged = false
+	}
+
+	s.batchDepth++
+}
+
+// End ends a batch started by Begin and shows the updated content. It does
+// nothing without a matching Begin.
+func (s *SevSeg) End() {
+	s.lock()
+	defer s.unlock()
+
+	if s.batchDepth == 0 {
+		return
+	}
+
+	s.batchDepth--
+	if s.batchDepth > 0 {
+		return
+	}
+
+	if s.batchChanged {
+		s.publishFrame()
+	}
+
+	s.invalidate()
+}
+
+// batchPattern returns the pattern of the given slot while a batch holds the
+// frame back.
+func (s *SevSeg) batchPattern(slot uint8) (uint8, bool) {
+	if s.batchDepth == 0 {
+		return 0, false
+	}
+
+	return s.batchFrame[slot], true
+}
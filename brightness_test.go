@@ -0,0 +1,126 @@
+package sevseg
+
+import "testing"
+
+// hardwareLevel returns the native brightness level the backend of the
+// display was set to, and the maximum level of the backend.
+func hardwareLevel(t *testing.T, s *SevSeg) (level, maxLevel uint8) {
+	t.Helper()
+
+	switch b := s.backend.(type) {
+	case *tm1637:
+		return b.control &^ tm1637DisplayOn, 7
+	case *tm1638:
+		return b.control &^ tm1638DisplayOn, 7
+	case *max7219:
+		return b.intensity, 15
+	case *ht16k33:
+		return b.level, 15
+	}
+
+	t.Fatalf("unexpected backend %T", s.backend)
+
+	return 0, 0
+}
+
+func TestSetBrightnessLevelTopStep(t *testing.T) {
+	configs := map[string]Config{
+		"TM1637":  {Controller: ControllerTM1637, ControllerDigits: 4},
+		"TM1638":  {Controller: ControllerTM1638, ControllerDigits: 8},
+		"MAX7219": {Controller: ControllerMAX7219, ControllerDigits: 8, ControllerSPI: newFakeSPI()},
+		"HT16K33": {Controller: ControllerHT16K33, ControllerDigits: 4, ControllerI2C: &fakeI2C{}},
+	}
+
+	for name, cfg := range configs {
+		t.Run(name, func(t *testing.T) {
+			display, err := NewSevSeg(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, maxLevels := range []uint8{1, 4, 8, 16, 100} {
+				if err := display.SetBrightnessLevel(maxLevels, maxLevels); err != nil {
+					t.Fatal(err)
+				}
+				display.Refresh()
+
+				if level, maxLevel := hardwareLevel(t, display); level != maxLevel {
+					t.Errorf("level %d of %d: hardware level = %d, want %d", maxLevels, maxLevels, level, maxLevel)
+				}
+			}
+		})
+	}
+}
+
+func TestControllerBrightnessLevels(t *testing.T) {
+	tm37 := newTM1637(machineNoPin, machineNoPin, 4)
+	tm38 := newTM1638(machineNoPin, machineNoPin, machineNoPin)
+	spi := newFakeSPI()
+	mx := newMAX7219(spi, machineNoPin, 8)
+	i2c := &fakeI2C{}
+	ht := newHT16K33(i2c, 0, 4)
+
+	// The state sent to the controllers is read back, the level only counts
+	// while the display is on
+	controllers := []struct {
+		name     string
+		set      func(brightness uint8)
+		on       func() bool
+		level    func() uint8
+		maxLevel uint8
+	}{
+		{
+			"TM1637", tm37.setBrightness,
+			func() bool { return tm37.control&tm1637DisplayOn == tm1637DisplayOn },
+			func() uint8 { return tm37.control &^ tm1637DisplayOn }, 7,
+		},
+		{
+			"TM1638", tm38.setBrightness,
+			func() bool { return tm38.control&tm1638DisplayOn == tm1638DisplayOn },
+			func() uint8 { return tm38.control &^ tm1638DisplayOn }, 7,
+		},
+		{
+			"MAX7219", mx.setBrightness,
+			func() bool { return spi.registers(0)[max7219Shutdown] == 1 },
+			func() uint8 { return spi.registers(0)[max7219Intensity] }, 15,
+		},
+		{
+			"HT16K33", ht.setBrightness,
+			func() bool { return i2c.on },
+			func() uint8 { return i2c.dimming }, 15,
+		},
+	}
+
+	for _, c := range controllers {
+		t.Run(c.name, func(t *testing.T) {
+			c.set(0)
+			if c.on() {
+				t.Fatal("brightness 0: display on")
+			}
+
+			previous := uint8(0)
+			for brightness := uint8(1); brightness <= 100; brightness++ {
+				c.set(brightness)
+
+				if !c.on() {
+					t.Fatalf("brightness %d: display off", brightness)
+				}
+
+				level := c.level()
+				if level > c.maxLevel {
+					t.Fatalf("brightness %d: level %d exceeds %d", brightness, level, c.maxLevel)
+				}
+
+				if level < previous {
+					t.Fatalf("brightness %d: level %d below level %d of brightness %d", brightness, level, previous, brightness-1)
+				}
+
+				previous = level
+			}
+
+			if previous != c.maxLevel {
+				t.Errorf("brightness 100: level = %d, want %d", previous, c.maxLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package sevseg
+
+// SetBurnInShift evens out the aging of the LEDs on displays showing the
+// same content for months, e.g. a label. Every periodTicks ticks of the
+// effects clock, the content is rotated by one more digit to the left,
+// wrapping around at the left end, so each physical digit shows every
+// position in turn. It's meant for static content whose position doesn't
+// matter, like a short label padded with blanks. A period of 0 stops the
+// rotation and shows the content at its position again.
+func (s *SevSeg) SetBurnInShift(periodTicks uint16) {
+	s.lock()
+	defer s.unlock()
+
+	s.burnInPeriod = periodTicks
+	s.burnInNext = s.deadline(periodTicks)
+	s.burnInOffset = 0
+	s.invalidate()
+}
+
+// advanceBurnIn advances the burn-in rotation by one tick of the effects
+// clock.
+func (s *SevSeg) advanceBurnIn() {
+	if s.burnInPeriod == 0 {
+		return
+	}
+
+	if s.expired(&s.burnInNext, s.burnInPeriod) {
+		s.burnInOffset = (s.burnInOffset + 1) % uint8(len(s.digitPins))
+		s.invalidate()
+	}
+}
+
+// burnInDigit returns the digit of the content shown on the given physical
+// digit with the current burn-in rotation.
+func (s *SevSeg) burnInDigit(slot uint8) uint8 {
+	width := uint8(len(s.digitPins))
+
+	return (slot + width - s.burnInOffset) % width
+}
@@ -0,0 +1,185 @@
+package sevseg
+
+import (
+	"io"
+	"slices"
+)
+
+// calibrationVersion is the version of the encoded calibration format. It is
+// increased whenever fields are added to Calibration.
+const calibrationVersion = 2
+
+// calibrationHeaderSize is the size of the encoded calibration in bytes
+// without the dead segments: the version, the brightness, the flags and the
+// number of digits with dead segments.
+const calibrationHeaderSize = 4
+
+// calibrationV1Size is the size of a calibration of version 1, which only
+// holds the brightness.
+const calibrationV1Size = 2
+
+// calibrationSegmentCompensation is the flag of SegmentCompensation.
+const calibrationSegmentCompensation = 1 << 0
+
+// Calibration holds the display settings which are calibrated in the field,
+// so they can be persisted across reboots.
+type Calibration struct {
+	// Brightness is the brightness in percentage (0-100).
+	Brightness uint8
+
+	// SegmentCompensation is the setting of the same name in Config.
+	SegmentCompensation bool
+
+	// DeadSegments holds the segments declared with MarkSegmentDead, a bit
+	// mask per digit starting with the right most one; nil if there are none.
+	DeadSegments []uint8
+}
+
+// Storage is implemented by persistent memory such as an EEPROM or the
+// on-chip flash (machine.Flash). Note that flash must be erased before it can
+// be written.
+type Storage interface {
+	ReadAt(p []byte, off int64) (n int, err error)
+	WriteAt(p []byte, off int64) (n int, err error)
+}
+
+// MarshalBinary encodes the calibration, prefixed with the format version.
+//
+// Returns ErrInvalidCalibration if there are dead segments on more than 255
+// digits.
+func (c Calibration) MarshalBinary() ([]byte, error) {
+	if len(c.DeadSegments) > 255 {
+		return nil, ErrInvalidCalibration
+	}
+
+	var flags uint8
+	if c.SegmentCompensation {
+		flags |= calibrationSegmentCompensation
+	}
+
+	data := make([]byte, 0, calibrationHeaderSize+len(c.DeadSegments))
+	data = append(data, calibrationVersion, c.Brightness, flags, uint8(len(c.DeadSegments)))
+
+	return append(data, c.DeadSegments...), nil
+}
+
+// UnmarshalBinary decodes a calibration encoded by MarshalBinary. Calibrations
+// of version 1, which only hold the brightness, are accepted as well.
+func (c *Calibration) UnmarshalBinary(data []byte) error {
+	if len(data) < calibrationV1Size || data[1] > 100 {
+		return ErrInvalidCalibration
+	}
+
+	switch data[0] {
+	case 1:
+		*c = Calibration{Brightness: data[1]}
+
+		return nil
+	case calibrationVersion:
+	default:
+		return ErrInvalidCalibration
+	}
+
+	if len(data) < calibrationHeaderSize || data[2]&^calibrationSegmentCompensation != 0 ||
+		len(data) < calibrationHeaderSize+int(data[3]) {
+		return ErrInvalidCalibration
+	}
+
+	*c = Calibration{
+		Brightness:          data[1],
+		SegmentCompensation: data[2]&calibrationSegmentCompensation != 0,
+	}
+
+	if dead := data[calibrationHeaderSize : calibrationHeaderSize+int(data[3])]; len(dead) > 0 {
+		c.DeadSegments = slices.Clone(dead)
+	}
+
+	return nil
+}
+
+// Calibration returns the current calibration of the display.
+func (s *SevSeg) Calibration() Calibration {
+	s.lock()
+	defer s.unlock()
+
+	return Calibration{
+		Brightness:          s.brightness,
+		SegmentCompensation: s.segmentDuty,
+		DeadSegments:        slices.Clone(s.deadSegments),
+	}
+}
+
+// ApplyCalibration applies a calibration to the display. Dead segments of
+// digits or segments the display doesn't have are ignored.
+func (s *SevSeg) ApplyCalibration(c Calibration) {
+	s.lock()
+	defer s.unlock()
+
+	s.SetBrightness(c.Brightness)
+	s.segmentDuty = c.SegmentCompensation
+
+	s.ClearDeadSegments()
+	for position, dead := range c.DeadSegments {
+		for segment := range s.segmentCount() {
+			if dead&(1<<segment) != 0 {
+				_ = s.MarkSegmentDead(uint8(position), segment)
+			}
+		}
+	}
+}
+
+// SaveCalibration writes the current calibration to the storage at the given
+// offset.
+func (s *SevSeg) SaveCalibration(storage Storage, offset int64) error {
+	s.lock()
+	defer s.unlock()
+
+	data, err := s.Calibration().MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	n, err := storage.WriteAt(data, offset)
+	if err != nil {
+		return err
+	}
+
+	if n != len(data) {
+		return io.ErrShortWrite
+	}
+
+	return nil
+}
+
+// LoadCalibration reads a calibration from the storage at the given offset
+// and applies it to the display.
+//
+// Returns the error of the storage if it couldn't be read, or
+// ErrInvalidCalibration if it holds no valid calibration, e.g. because it has
+// never been written.
+func (s *SevSeg) LoadCalibration(storage Storage, offset int64) error {
+	s.lock()
+	defer s.unlock()
+
+	data := make([]byte, calibrationHeaderSize)
+	if _, err := storage.ReadAt(data, offset); err != nil {
+		return err
+	}
+
+	// The dead segments follow the header
+	if data[0] == calibrationVersion && data[3] > 0 {
+		data = append(data, make([]byte, data[3])...)
+		if _, err := storage.ReadAt(data[calibrationHeaderSize:], offset+calibrationHeaderSize); err != nil {
+			return err
+		}
+	}
+
+	var c Calibration
+	if err := c.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	s.ApplyCalibration(c)
+
+	return nil
+}
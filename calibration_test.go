@@ -0,0 +1,116 @@
+//go:build !tinygo
+
+package sevseg
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// memoryStorage is a Storage in RAM.
+type memoryStorage [64]byte
+
+func (m *memoryStorage) ReadAt(p []byte, off int64) (int, error) {
+	return copy(p, m[off:]), nil
+}
+
+func (m *memoryStorage) WriteAt(p []byte, off int64) (int, error) {
+	return copy(m[off:], p), nil
+}
+
+func TestCalibrationEncoding(t *testing.T) {
+	tests := []Calibration{
+		{Brightness: 0},
+		{Brightness: 100, SegmentCompensation: true},
+		{Brightness: 42, DeadSegments: []uint8{0b00000001, 0, 0b10000000, 0b01000000}},
+	}
+
+	for _, want := range tests {
+		data, err := want.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if data[0] != calibrationVersion {
+			t.Errorf("%+v: version %d, want %d", want, data[0], calibrationVersion)
+		}
+
+		var got Calibration
+		if err := got.UnmarshalBinary(data); err != nil {
+			t.Fatalf("%+v: %v", want, err)
+		}
+
+		if got.Brightness != want.Brightness || got.SegmentCompensation != want.SegmentCompensation ||
+			!slices.Equal(got.DeadSegments, want.DeadSegments) {
+			t.Errorf("got %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestCalibrationVersion1(t *testing.T) {
+	var c Calibration
+	if err := c.UnmarshalBinary([]byte{1, 70}); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Brightness != 70 || c.SegmentCompensation || c.DeadSegments != nil {
+		t.Errorf("got %+v, want brightness 70 only", c)
+	}
+}
+
+func TestCalibrationInvalid(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{0xFF, 0xFF, 0xFF, 0xFF}, // Erased flash
+		{calibrationVersion, 101, 0, 0},
+		{calibrationVersion, 50, 0},
+		{calibrationVersion, 50, 0x80, 0},
+		{calibrationVersion, 50, 0, 2, 0x01},
+	}
+
+	for _, data := range tests {
+		var c Calibration
+		if err := c.UnmarshalBinary(data); !errors.Is(err, ErrInvalidCalibration) {
+			t.Errorf("%v: err = %v, want ErrInvalidCalibration", data, err)
+		}
+	}
+}
+
+func TestSaveLoadCalibration(t *testing.T) {
+	cfg := Config{
+		DigitPins:   SimulatedPins(4),
+		SegmentPins: SimulatedPins(8),
+	}
+
+	saved, err := NewSevSeg(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved.SetBrightness(60)
+	saved.segmentDuty = true
+	if err := saved.MarkSegmentDead(2, 6); err != nil {
+		t.Fatal(err)
+	}
+
+	var storage memoryStorage
+	if err := saved.SaveCalibration(&storage, 8); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewSevSeg(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := loaded.LoadCalibration(&storage, 8); err != nil {
+		t.Fatal(err)
+	}
+
+	want, got := saved.Calibration(), loaded.Calibration()
+	if got.Brightness != want.Brightness || got.SegmentCompensation != want.SegmentCompensation ||
+		!slices.Equal(got.DeadSegments, want.DeadSegments) {
+		t.Errorf("loaded %+v, want %+v", got, want)
+	}
+}
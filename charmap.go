@@ -0,0 +1,67 @@
+package sevseg
+
+// characterMap holds the characters of the font, each at its index in a
+// Font.
+const characterMap = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ -.*_"
+
+// ShowCharacterMap cycles through every glyph the display can show, one every
+// ticksPerChar ticks of the effects clock, e.g. to verify a font or the
+// wiring. The glyph is shown on the leftmost digit and, on displays with at
+// least 3 digits, its index in the font on the rightmost two. Characters
+// without a glyph, like M and W, and the decimal point on displays without a
+// DP pin are skipped. The map loops until other content is set.
+//
+// Returns ErrInvalidArgument if ticksPerChar is 0.
+func (s *SevSeg) ShowCharacterMap(ticksPerChar uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if ticksPerChar == 0 {
+		return ErrInvalidArgument
+	}
+
+	s.StopFrames()
+	s.charMapPeriod = ticksPerChar
+	s.charMapNext = s.deadline(ticksPerChar)
+	s.charMapIndex = 0
+	s.showCharacter()
+
+	return nil
+}
+
+// advanceCharacterMap advances the character map by one tick of the effects
+// clock.
+func (s *SevSeg) advanceCharacterMap() {
+	if s.charMapPeriod == 0 {
+		return
+	}
+
+	if s.expired(&s.charMapNext, s.charMapPeriod) {
+		s.charMapIndex = (s.charMapIndex + 1) % uint8(len(characterMap))
+		s.showCharacter()
+	}
+}
+
+// showCharacter commits the current glyph of the character map, skipping
+// characters without a glyph.
+func (s *SevSeg) showCharacter() {
+	pattern, ok := s.format.Glyph(characterMap[s.charMapIndex])
+	for !ok || pattern == 0 {
+		s.charMapIndex = (s.charMapIndex + 1) % uint8(len(characterMap))
+		pattern, ok = s.format.Glyph(characterMap[s.charMapIndex])
+	}
+
+	for i := range s.updatedDisplay {
+		s.updatedDisplay[i] = s.getSegmentCode(36) // BLANK
+	}
+
+	width := len(s.updatedDisplay)
+	s.updatedDisplay[width-1] = pattern
+
+	if width >= 3 {
+		s.updatedDisplay[1] = s.getSegmentCode(s.charMapIndex / 10)
+		s.updatedDisplay[0] = s.getSegmentCode(s.charMapIndex % 10)
+	}
+
+	s.commit(ContentAnimation)
+}
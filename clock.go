@@ -0,0 +1,80 @@
+package sevseg
+
+// The timed effects like AlternateWith, AutoScroll or PlayFrames are driven
+// by a single effects clock instead of counting Refresh calls each on their
+// own. The clock ticks once per full frame, i.e. whenever the multiplexing
+// of a display wraps around to the first digit, and on every Refresh call
+// with a controller. While the software PWM is off, the multiplexing waits on
+// the current digit, so a frame takes more than scanSteps calls at a reduced
+// brightness. The effects advance together at the start of a frame, so the
+// content never changes in the middle of a frame (see
+// TestContentChangesBetweenFrames), and effects started at the same time with
+// the same period stay in step.
+
+// advanceClock advances the effects clock by one Refresh call. Returns true
+// if a new frame starts, i.e. the clock has to tick.
+func (s *SevSeg) advanceClock() bool {
+	// nextSlot marks the start of a frame when the multiplexing wraps, which
+	// takes more than scanSteps calls while the software PWM is off
+	if s.backend == nil {
+		if !s.frameStart {
+			return false
+		}
+
+		s.frameStart = false
+	}
+
+	return true
+}
+
+// tick advances the effects clock and all timed effects by one tick, or
+// leaves it to the effects goroutine while a RefreshTimer is running.
+func (s *SevSeg) tick() {
+	if s.timerRunning {
+		s.pendingTicks.Add(1)
+		return
+	}
+
+	s.clock++
+	s.advanceEffects()
+}
+
+// runPendingTicks runs the ticks counted by the timer interrupt.
+func (s *SevSeg) runPendingTicks() {
+	for n := s.pendingTicks.Swap(0); n > 0; n-- {
+		s.clock++
+		s.advanceEffects()
+	}
+}
+
+// deadline returns the clock value the given number of ticks from now.
+func (s *SevSeg) deadline(ticks uint16) uint32 {
+	return s.clock + uint32(ticks)
+}
+
+// expired reports whether the clock reached the deadline. If so, the
+// deadline is moved the given number of ticks ahead.
+func (s *SevSeg) expired(deadline *uint32, ticks uint16) bool {
+	if int32(s.clock-*deadline) < 0 {
+		return false
+	}
+
+	*deadline = s.deadline(ticks)
+
+	return true
+}
+
+// advanceEffects advances all timed effects by one tick of the clock.
+func (s *SevSeg) advanceEffects() {
+	s.advancePlayback()
+	s.advanceCharacterMap()
+	s.advanceAutoScroll()
+	s.advanceAlternate()
+	s.advanceWarning()
+	s.advanceHighlight()
+	s.advanceOrientation()
+	s.advanceAnimations()
+	s.advanceBurnIn()
+	s.advanceClockFace()
+	s.advanceCrossfade()
+}
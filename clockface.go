@@ -0,0 +1,113 @@
+package sevseg
+
+// defaultClockBlink is the default number of ticks of the effects clock the
+// separator of SetClock stays on and off, about half a second at a typical
+// refresh rate.
+const defaultClockBlink = 100
+
+// SetClock shows the time of day as HH MM on the rightmost four digits,
+// separated by the colon if ColonSegments or a ColonPin are configured, or
+// the decimal point otherwise, e.g. " 9:05". The hours are zero padded with
+// UseLeadingZeros. The separator blinks on the effects clock, see
+// SetClockBlink, so it only needs to be called when the minute changes. The
+// blinking stops when other content is set, including by SetColon, and other
+// content turns the colon off.
+//
+// Returns ErrInvalidArgument for hours above 23 or minutes above 59,
+// ErrTooManyDigits on displays with fewer than 4 digits or
+// ErrNoDecimalPointPin if there is neither a colon nor a decimal point.
+func (s *SevSeg) SetClock(hours, minutes uint8) error {
+	s.lock()
+	defer s.unlock()
+
+	if hours > 23 || minutes > 59 {
+		return ErrInvalidArgument
+	}
+
+	if len(s.updatedDisplay) < 4 {
+		return ErrTooManyDigits
+	}
+
+	if !s.hasColon() && !s.HasDecimalPoint() {
+		return ErrNoDecimalPointPin
+	}
+
+	for i := range s.updatedDisplay {
+		s.updatedDisplay[i] = s.getSegmentCode(36) // BLANK
+	}
+
+	s.format.Digits(s.updatedDisplay[:2], uint32(minutes), false, 10, 2)
+	s.format.Digits(s.updatedDisplay[2:4], uint32(hours), false, 10, 1)
+
+	if !s.hasColon() {
+		s.updatedDisplay[2] |= s.getSegmentCode(38) // DECIMAL POINT
+	}
+
+	s.commitSeparated()
+
+	s.clockFace = true
+	s.clockBlinkOff = false
+	s.clockBlinkNext = s.deadline(s.clockBlinkPeriod)
+
+	return nil
+}
+
+// SetClockBlink sets the number of ticks of the effects clock the separator
+// of SetClock stays on and off, 100 by default. A period of 0 shows the
+// separator steadily.
+func (s *SevSeg) SetClockBlink(periodTicks uint16) {
+	s.lock()
+	defer s.unlock()
+
+	s.clockBlinkPeriod = periodTicks
+	s.clockBlinkNext = s.deadline(periodTicks)
+
+	if periodTicks == 0 && s.clockBlinkOff {
+		s.toggleClockSeparator()
+	}
+}
+
+// advanceClockFace blinks the separator of SetClock on a tick of the effects
+// clock.
+func (s *SevSeg) advanceClockFace() {
+	if !s.clockFace || s.clockBlinkPeriod == 0 {
+		return
+	}
+
+	if s.expired(&s.clockBlinkNext, s.clockBlinkPeriod) {
+		s.toggleClockSeparator()
+	}
+}
+
+// toggleClockSeparator turns the separator of SetClock on or off without
+// committing the content.
+func (s *SevSeg) toggleClockSeparator() {
+	s.clockBlinkOff = !s.clockBlinkOff
+
+	if s.hasColon() {
+		s.setColonLit(!s.clockBlinkOff)
+	}
+
+	s.invalidate()
+}
+
+// endClockFace stops the blinking of SetClock, leaving the separator on as
+// it was committed.
+func (s *SevSeg) endClockFace() {
+	if s.clockFace && s.clockBlinkOff && s.hasColon() {
+		s.setColonLit(true)
+	}
+
+	s.clockFace = false
+	s.clockBlinkOff = false
+}
+
+// clockSeparatorMask returns the segments of the given digit hidden by the
+// blinking of SetClock.
+func (s *SevSeg) clockSeparatorMask(digit uint8) uint8 {
+	if s.clockBlinkOff && !s.hasColon() && digit == 2 {
+		return 0b10000000
+	}
+
+	return 0
+}
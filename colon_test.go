@@ -0,0 +1,85 @@
+//go:build !tinygo
+
+package sevseg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSeparatorColon(t *testing.T) {
+	s, err := NewSevSeg(Config{
+		DigitPins:   SimulatedPins(4),
+		SegmentPins: SimulatedPins(8),
+		ColonPin:    machinePin(20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name string
+		set  func() error
+		lit  bool
+	}{
+		{"SetClock(9, 5)", func() error { return s.SetClock(9, 5) }, true},
+		{"SetNumber(12)", func() error { return s.SetNumber(12) }, false},
+		{"SetDuration(65s)", func() error { return s.SetDuration(65*time.Second, DurationMinutesSeconds) }, true},
+		{"SetText(\"AB\")", func() error { return s.SetText("AB") }, false},
+		{"SetPair(1, 2, Colon)", func() error { return s.SetPair(1, 2, Glyph.Colon) }, true},
+		{"UpdateLowestDigits(1, 3)", func() error { return s.UpdateLowestDigits(1, 3) }, true},
+		{"SetPair(1, 2, Dash)", func() error { return s.SetPair(1, 2, Glyph.Dash) }, false},
+		{"SetColon(true)", func() error { return s.SetColon(true) }, true},
+		{"SetNumber(34)", func() error { return s.SetNumber(34) }, true},
+		{"SetClock(10, 30)", func() error { return s.SetClock(10, 30) }, true},
+		{"SetHex(0xAB)", func() error { return s.SetHex(0xAB) }, false},
+	}
+
+	for _, step := range steps {
+		if err := step.set(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		if s.colonLit != step.lit {
+			t.Errorf("%s: colon lit %t, want %t", step.name, s.colonLit, step.lit)
+		}
+	}
+}
+
+func TestSeparatorColonWhileBlinking(t *testing.T) {
+	s, err := NewSevSeg(Config{
+		DigitPins:         SimulatedPins(4),
+		SegmentPins:       SimulatedPins(8),
+		ColonSegments:     0b00000011,
+		ClockIndicatorPin: machinePin(20),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.SetClockBlink(1)
+	if err := s.SetClock(9, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	// Until the blinking turned the colon off
+	for range 100 {
+		if s.clockBlinkOff {
+			break
+		}
+
+		s.Refresh()
+	}
+
+	if !s.clockBlinkOff {
+		t.Fatal("colon didn't blink")
+	}
+
+	if err := s.SetNumber(12); err != nil {
+		t.Fatal(err)
+	}
+
+	if colon := s.auxDisplay[s.auxLEDGroups] & 0b00000011; colon != 0 {
+		t.Errorf("colon segments %02b lit after SetNumber, want off", colon)
+	}
+}
@@ -0,0 +1,176 @@
+package sevseg
+
+type color uint8
+
+// Color defines the colors of tri-color digits for SetColor. The values are
+// the buses lit for a color: bit 0 is the red bus (SegmentPins), bit 1 the
+// green bus (GreenSegmentPins) and bit 2 the blue bus (BlueSegmentPins).
+var Color = struct {
+	Off     color
+	Red     color
+	Green   color
+	Amber   color
+	Blue    color
+	Magenta color
+	Cyan    color
+	White   color
+}{
+	Off:     0b000,
+	Red:     0b001,
+	Green:   0b010,
+	Amber:   0b011, // Red and green
+	Blue:    0b100,
+	Magenta: 0b101, // Red and blue
+	Cyan:    0b110, // Green and blue
+	White:   0b111,
+}
+
+// colorBuses is the number of segment buses of tri-color digits.
+const colorBuses = 3
+
+// validColorBuses reports whether the green and blue buses of the
+// configuration match the SegmentPins. They can't be combined with a shift
+// register.
+func validColorBuses(cfg Config) bool {
+	for _, pins := range [][]OutputPin{cfg.GreenSegmentPins, cfg.BlueSegmentPins} {
+		if len(pins) == 0 {
+			continue
+		}
+
+		if cfg.ShiftRegister != NoShiftRegister || len(pins) != len(cfg.SegmentPins) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetColor sets the color of the digit at position (zero indexed from the
+// right), e.g. to color-code a value (green, amber, red) on supported
+// hardware. The color stays when the content changes. All digits are red
+// initially, the color of the SegmentPins bus.
+//
+// The buses of a mixed color like amber are lit one after another, each for
+// one call of Refresh, so the digit select line only carries the current of
+// one bus at a time.
+//
+// Returns ErrNotConfigured if neither GreenSegmentPins nor BlueSegmentPins
+// are configured, ErrOutOfRange if the position is invalid and
+// ErrInvalidArgument if the color uses a bus which isn't configured.
+func (s *SevSeg) SetColor(position uint8, c color) error {
+	s.lock()
+	defer s.unlock()
+
+	if s.colors == nil {
+		return ErrNotConfigured
+	}
+
+	if position >= uint8(len(s.digitPins)) {
+		return ErrOutOfRange
+	}
+
+	if !s.hasColor(c) {
+		return ErrInvalidArgument
+	}
+
+	s.colors[position] = c
+	s.invalidate()
+
+	return nil
+}
+
+// SetColors sets the color of all digits, see SetColor.
+func (s *SevSeg) SetColors(c color) error {
+	s.lock()
+	defer s.unlock()
+
+	if s.colors == nil {
+		return ErrNotConfigured
+	}
+
+	if !s.hasColor(c) {
+		return ErrInvalidArgument
+	}
+
+	for i := range s.colors {
+		s.colors[i] = c
+	}
+
+	s.invalidate()
+
+	return nil
+}
+
+// hasColor reports whether all buses of the color are configured.
+func (s *SevSeg) hasColor(c color) bool {
+	for bus := range colorBuses {
+		if c&(1<<bus) != 0 && s.colorBusPins[bus] == nil {
+			return false
+		}
+	}
+
+	return c <= Color.White
+}
+
+// slotColor returns the color of the given multiplex slot. The aux digits
+// only use the red bus.
+func (s *SevSeg) slotColor(slot uint8) color {
+	if slot >= uint8(len(s.digitPins)) {
+		return Color.Red
+	}
+
+	if s.upsideDown {
+		slot = uint8(len(s.digitPins)) - 1 - slot
+	}
+
+	return s.colors[slot]
+}
+
+// firstColorBus selects the first bus of the color of the current slot, or
+// none if the slot is off.
+func (s *SevSeg) firstColorBus() {
+	s.currentColorBus = colorBuses
+	s.nextColorBus()
+}
+
+// nextColorBus selects the next bus of the color of the current slot.
+// Returns false if there is none.
+func (s *SevSeg) nextColorBus() bool {
+	c := s.slotColor(s.currentDigitToRefresh)
+
+	bus := s.currentColorBus + 1
+	if s.currentColorBus == colorBuses {
+		bus = 0
+	}
+
+	for ; bus < colorBuses; bus++ {
+		if c&(1<<bus) != 0 {
+			s.currentColorBus = bus
+			return true
+		}
+	}
+
+	s.currentColorBus = colorBuses
+
+	return false
+}
+
+// setColorSegmentPins writes the pattern to the bus selected for the current
+// slot and turns the segments of all other buses off.
+func (s *SevSeg) setColorSegmentPins(pattern uint8) {
+	// The color may have changed since the bus was selected
+	if s.slotColor(s.currentDigitToRefresh)&(1<<s.currentColorBus) == 0 {
+		s.firstColorBus()
+	}
+
+	for bus, pins := range s.colorBusPins {
+		busPattern := uint8(0)
+		if bus == int(s.currentColorBus) {
+			busPattern = pattern
+		}
+
+		for i, pin := range pins {
+			s.setSegmentPin(pin, (busPattern&(1<<i)) != 0)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package sevseg
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ProcessCommand executes a text command, e.g. read from a serial terminal,
+// so a display can be exercised during bring-up without code changes. The
+// supported commands are:
+//
+//	num <number>          SetNumber
+//	hex <number>          SetHex, the number is given in hexadecimal
+//	text <text>           SetText, the rest of the line is the text
+//	bright <0-100>        SetBrightness
+//	scroll left|right     ScrollTextLeft or ScrollTextRight
+//	clear                 Clear
+//	on                    On
+//	off                   Off
+//
+// Returns ErrUnknownCommand for unknown commands, ErrInvalidArgument for
+// invalid arguments and the error of the called method if it fails.
+func (s *SevSeg) ProcessCommand(line string) error {
+	s.lock()
+	defer s.unlock()
+
+	command, argument, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if command != "text" {
+		argument = strings.TrimSpace(argument)
+	}
+
+	switch command {
+	case "num":
+		number, err := strconv.ParseInt(argument, 10, 32)
+		if err != nil {
+			return ErrInvalidArgument
+		}
+
+		return s.SetNumber(int32(number))
+	case "hex":
+		number, err := strconv.ParseUint(argument, 16, 32)
+		if err != nil {
+			return ErrInvalidArgument
+		}
+
+		return s.SetHex(uint32(number))
+	case "text":
+		return s.SetText(argument)
+	case "bright":
+		brightness, err := strconv.ParseUint(argument, 10, 8)
+		if err != nil {
+			return ErrInvalidArgument
+		}
+
+		s.SetBrightness(uint8(brightness))
+	case "scroll":
+		switch argument {
+		case "left":
+			s.ScrollTextLeft()
+		case "right":
+			s.ScrollTextRight()
+		default:
+			return ErrInvalidArgument
+		}
+	case "clear":
+		s.Clear()
+	case "on":
+		s.On()
+	case "off":
+		s.Off()
+	default:
+		return ErrUnknownCommand
+	}
+
+	return nil
+}
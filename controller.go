@@ -0,0 +1,249 @@
+package sevseg
+
+type controllerType uint8
+
+// NoController, ControllerTM1637, ControllerMAX7219, ControllerHT16K33 and
+// ControllerTM1638 define whether the display is multiplexed by Refresh or
+// driven by a controller chip. The TM1637 is found on many cheap 4-digit
+// modules and is connected with a 2-wire protocol. The MAX7219 (or MAX7221)
+// drives up to 8 digits over SPI. The HT16K33 is used by Adafruit-style I2C
+// backpacks. The TM1638 is found on LED&KEY boards with 8 digits, 8 LEDs and
+// 8 keys.
+const (
+	NoController controllerType = iota
+	ControllerTM1637
+	ControllerMAX7219
+	ControllerHT16K33
+	ControllerTM1638
+)
+
+// backend is a controller chip which multiplexes the digits itself. It gets
+// the content when it changes instead of being driven by Refresh.
+type backend interface {
+	// writeFrame sends a frame, the first element being the right most digit.
+	writeFrame(frame []uint8)
+
+	// setBrightness sets the brightness in percent, 0 turns the display off.
+	setBrightness(brightness uint8)
+}
+
+// auxWriter is a backend which drives aux LEDs in addition to the digits.
+type auxWriter interface {
+	// writeAux sends the aux LED groups, see SetAuxLED.
+	writeAux(aux []uint8)
+}
+
+// keyReader is a backend which scans keys in addition to driving the digits.
+type keyReader interface {
+	readKeys() uint8
+}
+
+// moduleChain is a backend driving a chain of modules with the same number
+// of digits each, like chained MAX7219 modules.
+type moduleChain interface {
+	moduleCount() uint8
+}
+
+// writeVerifier is a backend which can read back the display memory to
+// verify the frames written to it.
+type writeVerifier interface {
+	// setVerify turns reading back every written frame on or off.
+	setVerify(on bool)
+
+	// writeError returns and clears the first failure since the last call.
+	writeError() error
+}
+
+// NewSevSegTM1637 creates a new instance of sevSeg for a 4-digit module with
+// a TM1637 controller, connected to the given clock (CLK) and data (DIO) pins.
+// Use NewSevSeg with Controller set to ControllerTM1637 for other digit counts
+// or further options.
+func NewSevSegTM1637(clk, dio machinePin) (*SevSeg, error) {
+	return NewSevSeg(Config{
+		Controller:         ControllerTM1637,
+		ControllerClockPin: clk,
+		ControllerDataPin:  dio,
+		ControllerDigits:   4,
+	})
+}
+
+// NewSevSegTM1638 creates a new instance of sevSeg for an LED&KEY board with a
+// TM1638 controller, connected to the given strobe (STB), clock (CLK) and
+// data (DIO) pins. The 8 LEDs are set with SetAuxLED, the 8 keys are read
+// with ReadKeys.
+func NewSevSegTM1638(stb, clk, dio machinePin) (*SevSeg, error) {
+	return NewSevSeg(Config{
+		Controller:         ControllerTM1638,
+		ControllerLoadPin:  stb,
+		ControllerClockPin: clk,
+		ControllerDataPin:  dio,
+		ControllerDigits:   8,
+	})
+}
+
+// ReadKeys returns the keys of the controller which are currently pressed,
+// bit i being set for the i-th key from the left.
+//
+// Returns ErrNotConfigured if the display isn't driven by a controller which
+// scans keys, like the TM1638.
+func (s *SevSeg) ReadKeys() (uint8, error) {
+	s.lock()
+	defer s.unlock()
+
+	reader, ok := s.backend.(keyReader)
+	if !ok {
+		return 0, ErrNotConfigured
+	}
+
+	return reader.readKeys(), nil
+}
+
+// SetWriteVerification turns the verification of written frames on or off.
+// When on, every frame sent to the controller is read back from its display
+// memory. A mismatch is reported by WriteError and the frame is written
+// again, which catches corruption on long cable runs. It costs a read of the
+// display memory per frame.
+//
+// Returns ErrNotConfigured if the display isn't driven by a controller which
+// can be read back, like the HT16K33.
+func (s *SevSeg) SetWriteVerification(on bool) error {
+	s.lock()
+	defer s.unlock()
+
+	verifier, ok := s.backend.(writeVerifier)
+	if !ok {
+		return ErrNotConfigured
+	}
+
+	verifier.setVerify(on)
+
+	return nil
+}
+
+// WriteError returns the first failure of the write verification since the
+// last call, ErrReadbackMismatch or the error of the bus, and clears it.
+// Returns nil if all frames were verified or the verification is off.
+func (s *SevSeg) WriteError() error {
+	s.lock()
+	defer s.unlock()
+
+	verifier, ok := s.backend.(writeVerifier)
+	if !ok {
+		return nil
+	}
+
+	return verifier.writeError()
+}
+
+// SetTextOnModule displays text on a single module of a chain of MAX7219
+// modules, module 0 being the left most one. It works like SetText on a
+// display of 8 digits, the other modules keep their content, so the chain can
+// be used as independent units as well as one wide display. The text isn't
+// scrolled.
+//
+// Returns ErrNotConfigured if the display isn't driven by MAX7219 modules,
+// ErrOutOfRange if the module doesn't exist, ErrTooManyDigits if the text is
+// longer than a module or ErrUnsupportedChar for unsupported characters.
+func (s *SevSeg) SetTextOnModule(module uint8, text string) error {
+	s.lock()
+	defer s.unlock()
+
+	chain, ok := s.backend.(moduleChain)
+	if !ok {
+		return ErrNotConfigured
+	}
+
+	modules := chain.moduleCount()
+	if module >= modules {
+		return ErrOutOfRange
+	}
+
+	width := len(s.updatedDisplay) / int(modules)
+	right := int(modules-1-module) * width
+	if err := s.format.Text(s.updatedDisplay[right:right+width], text); err != nil {
+		return err
+	}
+
+	// Scrolling a text set with SetText would overwrite the other modules
+	s.textPattern = nil
+	s.textString = ""
+
+	s.commit(ContentText)
+
+	return nil
+}
+
+// newControlledSevSeg creates a display driven by the controller of the
+// configuration.
+func newControlledSevSeg(cfg Config) (*SevSeg, error) {
+	if cfg.ControllerDigits == 0 || len(cfg.AuxDigitPins) > 0 ||
+		len(cfg.GreenSegmentPins) > 0 || len(cfg.BlueSegmentPins) > 0 || cfg.Decoder != NoDecoder ||
+		cfg.ColonSegments|cfg.ApostropheSegments|cfg.SignMinusSegments != 0 || cfg.ScanMode != ScanDigits ||
+		cfg.ColonPin != nil || cfg.ApostrophePin != nil {
+		return nil, ErrInvalidConfig
+	}
+
+	// The digits have no pins of their own, the placeholders only define the
+	// number of digits
+	digitPins := make([]OutputPin, cfg.ControllerDigits)
+	for i := range digitPins {
+		digitPins[i] = machineNoPin
+	}
+
+	s := newSevSeg(cfg, digitPins)
+	s.backendFrame = make([]uint8, len(digitPins))
+	s.liveDisplay = s.updatedDisplay // Sent right away, see stageFrame
+
+	switch {
+	case cfg.Controller == ControllerTM1637 && cfg.ControllerDigits <= 6:
+		s.backend = newTM1637(cfg.ControllerClockPin, cfg.ControllerDataPin, cfg.ControllerDigits)
+	case cfg.Controller == ControllerMAX7219 && validMAX7219Digits(cfg.ControllerDigits) && cfg.ControllerSPI != nil:
+		s.backend = newMAX7219(cfg.ControllerSPI, cfg.ControllerLoadPin, cfg.ControllerDigits)
+	case cfg.Controller == ControllerHT16K33 && cfg.ControllerDigits <= 7 && cfg.ControllerI2C != nil:
+		s.backend = newHT16K33(cfg.ControllerI2C, cfg.ControllerAddress, cfg.ControllerDigits)
+	case cfg.Controller == ControllerTM1638 && cfg.ControllerDigits <= 8:
+		s.backend = newTM1638(cfg.ControllerLoadPin, cfg.ControllerClockPin, cfg.ControllerDataPin)
+
+		// One aux LED group holds the 8 LEDs
+		s.auxLEDGroups = 1
+		s.auxDisplay = make([]uint8, 1)
+	default:
+		return nil, ErrInvalidConfig
+	}
+
+	s.format.DecimalPoint = true // Controllers drive all 8 segment lines
+
+	s.Clear()
+
+	return s, nil
+}
+
+// invalidate marks the content or brightness as changed. The multiplexing
+// picks up the change with the next Refresh, a controller gets it right away.
+func (s *SevSeg) invalidate() {
+	s.dirty = true
+
+	if s.backend != nil {
+		s.pushFrame()
+	}
+}
+
+// pushFrame sends the current content and brightness to the controller,
+// taking effects like AlternateWith and the orientation into account.
+func (s *SevSeg) pushFrame() {
+	for i := range s.backendFrame {
+		s.backendFrame[i] = s.slotPattern(uint8(i))
+	}
+
+	brightness := uint8(0)
+	if s.enabled && s.shown() {
+		brightness = s.currentBrightness()
+	}
+
+	if writer, ok := s.backend.(auxWriter); ok {
+		writer.writeAux(s.auxDisplay)
+	}
+
+	s.backend.writeFrame(s.backendFrame)
+	s.backend.setBrightness(brightness)
+}
@@ -0,0 +1,145 @@
+package sevseg
+
+import "time"
+
+// Countdown counts a duration down to zero on the display, measured with the
+// system clock. Call Update periodically, e.g. next to Refresh in the main
+// loop, to show the remaining time. When it reaches zero, the callback is
+// invoked once and Done reports true.
+type Countdown struct {
+	display *SevSeg
+	format  durationFormat
+	onDone  func()
+
+	// flashPeriod is the period of the flashing at zero in ticks of the
+	// effects clock, 0 to not flash
+	flashPeriod uint16
+
+	end      time.Time
+	shown    time.Duration // Remaining time on the display, rounded up
+	running  bool
+	done     bool
+	flashing bool
+}
+
+// NewCountdown creates a new countdown showing the remaining time on the
+// display in the format of SetDuration. onDone is invoked from Update when the
+// countdown reaches zero and may be nil.
+func NewCountdown(display *SevSeg, format durationFormat, onDone func()) (*Countdown, error) {
+	if display == nil || format > DurationHoursMinutes {
+		return nil, ErrInvalidArgument
+	}
+
+	c := &Countdown{
+		display: display,
+		format:  format,
+		onDone:  onDone,
+	}
+
+	return c, nil
+}
+
+// SetFlash flashes the display every periodTicks ticks of the effects clock
+// when the countdown reaches zero, until it's started again or stopped. A
+// period of 0 disables flashing, which is the default.
+func (c *Countdown) SetFlash(periodTicks uint16) {
+	c.flashPeriod = periodTicks
+}
+
+// Start starts counting the duration down, restarting a running countdown.
+//
+// Returns ErrInvalidArgument if the duration isn't positive or the error of
+// SetDuration, e.g. ErrTooManyDigits.
+func (c *Countdown) Start(duration time.Duration) error {
+	if duration <= 0 {
+		return ErrInvalidArgument
+	}
+
+	shown := c.roundUp(duration)
+	if err := c.display.SetDuration(shown, c.format); err != nil {
+		return err
+	}
+
+	c.stopFlash()
+	c.end = time.Now().Add(duration)
+	c.shown = shown
+	c.running = true
+	c.done = false
+
+	return nil
+}
+
+// Stop stops the countdown and its flashing, keeping the display as it is.
+// The callback isn't invoked.
+func (c *Countdown) Stop() {
+	c.running = false
+	c.stopFlash()
+}
+
+// Update shows the remaining time if it changed, and finishes the countdown
+// when it reaches zero.
+//
+// Returns whether the countdown is still running.
+func (c *Countdown) Update() bool {
+	if !c.running {
+		return false
+	}
+
+	remaining := time.Until(c.end)
+	if remaining > 0 {
+		if shown := c.roundUp(remaining); shown != c.shown {
+			c.display.SetDuration(shown, c.format)
+			c.shown = shown
+		}
+
+		return true
+	}
+
+	c.display.SetDuration(0, c.format)
+	c.shown = 0
+	c.running = false
+	c.done = true
+
+	if c.flashPeriod > 0 {
+		c.flashing = c.display.ShowWarning("", c.flashPeriod) == nil
+	}
+
+	if c.onDone != nil {
+		c.onDone()
+	}
+
+	return false
+}
+
+// Remaining returns the remaining time, 0 if the countdown isn't running.
+func (c *Countdown) Remaining() time.Duration {
+	if !c.running {
+		return 0
+	}
+
+	return max(time.Until(c.end), 0)
+}
+
+// Done reports whether the countdown has reached zero since it was started.
+func (c *Countdown) Done() bool {
+	return c.done
+}
+
+// roundUp rounds the remaining time up to the unit of the format, so zero is
+// only shown once the countdown has finished.
+func (c *Countdown) roundUp(remaining time.Duration) time.Duration {
+	unit := time.Second
+	if c.format == DurationHoursMinutes {
+		unit = time.Minute
+	}
+
+	return (remaining + unit - 1) / unit * unit
+}
+
+// stopFlash stops the flashing at zero.
+func (c *Countdown) stopFlash() {
+	if c.flashing {
+		c.display.ClearWarning()
+		c.flashing = false
+	}
+}
@@ -0,0 +1,145 @@
+package sevseg
+
+import "bytes"
+
+// SegmentLevelDriver is a Driver for hardware with a PWM channel per segment,
+// e.g. a PCA9685 or TLC5940, which can show segments at partial brightness.
+// If the driver set with SetMirror implements it, it receives the level of
+// every segment during the transitions of SetCrossfade, in addition to the
+// committed frames.
+type SegmentLevelDriver interface {
+	Driver
+
+	// WriteLevels sends the brightness of every segment in percent, 8
+	// segments per digit in the bit order of the segment patterns, starting
+	// with the right most digit. The levels are only valid during the call.
+	WriteLevels(levels []uint8)
+}
+
+// SetCrossfade fades from the old content to the new one over the given
+// number of frames whenever the content changes: the segments of the old
+// glyph fade out while the segments of the new one fade in. The multiplexed
+// display alternates whole frames between the old and the new content, showing
+// the new one more and more often. A SegmentLevelDriver set with SetMirror
+// gets the partial brightness of every segment instead. A length of 0 turns
+// crossfading off, which is the default. The buffers of the transitions are
+// allocated here, so Refresh doesn't allocate while fading.
+//
+// Returns ErrNotConfigured if the display is driven by a controller, which
+// doesn't support partial brightness.
+func (s *SevSeg) SetCrossfade(frames uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if s.backend != nil {
+		return ErrNotConfigured
+	}
+
+	s.endCrossfade()
+	s.fadeFrames = frames
+
+	if frames > 0 && s.fadeFrom == nil {
+		s.fadeFrom = make([]uint8, len(s.liveDisplay))
+		s.fadeLevels = make([]uint8, 8*len(s.liveDisplay))
+	}
+
+	return nil
+}
+
+// startCrossfade starts a transition from the live content to the staged one.
+func (s *SevSeg) startCrossfade() {
+	if s.fadeFrames == 0 || bytes.Equal(s.liveDisplay, s.stagedDisplay) {
+		return
+	}
+
+	// A transition which is still running restarts from what was shown last
+	if !s.fadeActive || s.fadeShowNew {
+		copy(s.fadeFrom, s.liveDisplay)
+	}
+
+	s.fadeActive = true
+	s.fadeStep = 0
+	s.fadeError = 0
+	s.fadeShowNew = false
+}
+
+// advanceCrossfade advances the transition by one tick of the effects clock.
+func (s *SevSeg) advanceCrossfade() {
+	if !s.fadeActive {
+		return
+	}
+
+	s.fadeStep++
+	if s.fadeStep >= s.fadeFrames {
+		s.endCrossfade()
+		s.writeLevels(100)
+
+		return
+	}
+
+	// Spreads the frames showing the new content evenly, like dithering
+	s.fadeError += s.fadeStep
+	s.fadeShowNew = s.fadeError >= s.fadeFrames
+	if s.fadeShowNew {
+		s.fadeError -= s.fadeFrames
+	}
+
+	s.writeLevels(uint8(uint32(s.fadeStep) * 100 / uint32(s.fadeFrames)))
+	s.invalidate()
+}
+
+// endCrossfade ends a running transition and shows the new content.
+func (s *SevSeg) endCrossfade() {
+	if !s.fadeActive {
+		return
+	}
+
+	s.fadeActive = false
+	s.fadeShowNew = false
+	s.invalidate()
+}
+
+// fadedPattern returns the live segment pattern of a digit, or the old one
+// during the frames of a transition showing the old content.
+func (s *SevSeg) fadedPattern(digit uint8) uint8 {
+	if s.fadeActive && !s.fadeShowNew {
+		return s.fadeFrom[digit]
+	}
+
+	return s.liveDisplay[digit]
+}
+
+// writeLevels sends the segment levels of the transition to the mirror if it
+// is a SegmentLevelDriver, the segments of the new content having the given
+// level in percent and those of the old content the rest.
+func (s *SevSeg) writeLevels(level uint8) {
+	driver, ok := s.mirror.(SegmentLevelDriver)
+	if !ok {
+		return
+	}
+
+	for digit, pattern := range s.liveDisplay {
+		old := pattern
+		if s.fadeActive {
+			old = s.fadeFrom[digit]
+		}
+
+		for segment := range 8 {
+			bit := uint8(1) << segment
+			entry := &s.fadeLevels[8*digit+segment]
+
+			switch {
+			case pattern&old&bit != 0:
+				*entry = 100
+			case pattern&bit != 0:
+				*entry = level
+			case old&bit != 0:
+				*entry = 100 - level
+			default:
+				*entry = 0
+			}
+		}
+	}
+
+	driver.WriteLevels(s.fadeLevels)
+}
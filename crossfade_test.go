@@ -0,0 +1,49 @@
+//go:build !tinygo
+
+package sevseg
+
+import "testing"
+
+func TestCrossfadeDoesNotAllocate(t *testing.T) {
+	s, err := NewSevSeg(Config{
+		DigitPins:   SimulatedPins(4),
+		SegmentPins: SimulatedPins(8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.SetCrossfade(8); err != nil {
+		t.Fatal(err)
+	}
+
+	number := int32(0)
+	transition := func() {
+		number++
+		if err := s.SetNumber(number); err != nil {
+			t.Fatal(err)
+		}
+
+		// Enough frames to start, run and end the transition
+		for range 4 * 10 {
+			s.Refresh()
+		}
+	}
+
+	transition()
+	if err := s.SetNumber(-1); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 4 {
+		s.Refresh()
+	}
+
+	if !s.fadeActive {
+		t.Fatal("content change didn't start a transition")
+	}
+
+	if allocs := testing.AllocsPerRun(20, transition); allocs != 0 {
+		t.Errorf("transition allocates %.1f times, want 0", allocs)
+	}
+}
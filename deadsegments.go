@@ -0,0 +1,83 @@
+package sevseg
+
+// glyphAlternates lists glyphs and an alternate with the same meaning which
+// uses other segments, e.g. a lowercase 'o' for '0'. A glyph may be listed
+// several times, the first alternate avoiding the dead segments is used.
+var glyphAlternates = [...][2]uint8{
+	{0b00111111, 0b01011100}, // '0', 'O' -> 'o'
+	{0b00000110, 0b00110000}, // '1' -> 'I' on the left
+	{0b00110000, 0b00000110}, // 'I' -> '1'
+	{0b01111101, 0b01111100}, // '6' -> 'b' shape
+	{0b01111100, 0b01111101}, // 'b' -> '6' shape
+	{0b00000111, 0b00100111}, // '7' -> '7' with F
+	{0b00100111, 0b00000111}, // '7' with F -> '7'
+	{0b01101111, 0b01100111}, // '9' -> 'q' shape
+	{0b01100111, 0b01101111}, // 'q' -> '9' shape
+	{0b00111001, 0b01011000}, // 'C' -> 'c'
+	{0b01011000, 0b00111001}, // 'c' -> 'C'
+	{0b00111110, 0b00011100}, // 'U' -> 'u'
+	{0b00011100, 0b00111110}, // 'u' -> 'U'
+	{0b01110110, 0b01110100}, // 'H' -> 'h'
+	{0b01110100, 0b01110110}, // 'h' -> 'H'
+	{0b01011100, 0b00111111}, // 'o' -> 'O'
+	{0b01010100, 0b00110111}, // 'n' -> 'N'
+}
+
+// MarkSegmentDead declares a segment of the digit at position (zero indexed
+// from the right) as dead, e.g. a burnt-out LED on salvaged hardware. The
+// segments are numbered 0-7 for A-G and DP. With Config.AvoidDeadSegments,
+// glyphs using a dead segment are replaced by an alternate with the same
+// meaning where one exists, e.g. a lowercase 'o' for '0' if segment A is dead.
+// The positions refer to the physical digits, also when the content is shown
+// upside down.
+//
+// Returns ErrOutOfRange if the digit or the segment doesn't exist.
+func (s *SevSeg) MarkSegmentDead(position, segment uint8) error {
+	s.lock()
+	defer s.unlock()
+
+	if position >= uint8(len(s.digitPins)) || segment >= s.segmentCount() {
+		return ErrOutOfRange
+	}
+
+	if s.deadSegments == nil {
+		s.deadSegments = make([]uint8, len(s.digitPins))
+	}
+
+	s.deadSegments[position] |= 1 << segment
+	s.invalidate()
+
+	return nil
+}
+
+// ClearDeadSegments removes the dead segments declared with MarkSegmentDead.
+func (s *SevSeg) ClearDeadSegments() {
+	s.lock()
+	defer s.unlock()
+
+	s.deadSegments = nil
+	s.invalidate()
+}
+
+// avoidDeadSegments returns an alternate of the pattern of the given digit
+// which doesn't use its dead segments, or the pattern itself if there is
+// none. The decimal point is kept.
+func (s *SevSeg) avoidDeadSegments(digit, pattern uint8) uint8 {
+	if !s.avoidDead || s.deadSegments == nil || s.decoder != NoDecoder {
+		return pattern
+	}
+
+	dead := s.deadSegments[digit]
+	glyph := pattern &^ 0b10000000
+	if glyph&dead == 0 {
+		return pattern
+	}
+
+	for _, alternate := range glyphAlternates {
+		if alternate[0] == glyph && alternate[1]&dead == 0 {
+			return alternate[1] | pattern&0b10000000
+		}
+	}
+
+	return pattern
+}
@@ -0,0 +1,54 @@
+package sevseg
+
+type decoderType uint8
+
+// NoDecoder and DecoderBCD define whether the segments are driven by a
+// BCD-to-7-segment decoder like the CD4511 (common cathode) or the 74HC47
+// (common anode). With DecoderBCD, the SegmentPins are the data inputs A-D of
+// the decoder, A being the least significant bit, optionally followed by a
+// pin driving the decimal point directly.
+//
+// A decoder can only show the digits 0-9 and blank. Other patterns, like the
+// minus of a negative number, are shown blank and SetText only accepts
+// digits, spaces and decimal points. AuxDigitPins, a clock indicator and a
+// sign element aren't supported.
+const (
+	NoDecoder decoderType = iota
+	DecoderBCD
+)
+
+// bcdBlank is the code blanking the digit, both the CD4511 and the 74HC47
+// show nothing for it.
+const bcdBlank = 0b1111
+
+// writeBCD writes the BCD code of the pattern to the data inputs of the
+// decoder, and its decimal point to the decimal point pin if there is one.
+func (s *SevSeg) writeBCD(pattern uint8) {
+	code := s.bcdCode(pattern)
+	for i, pin := range s.segmentPins[:4] {
+		setPin(pin, code&(1<<i) != 0)
+	}
+
+	if len(s.segmentPins) == 5 {
+		s.setSegmentPin(s.segmentPins[4], pattern&0b10000000 != 0)
+	}
+}
+
+// bcdCode returns the digit shown by the pattern, ignoring the decimal
+// point, or bcdBlank if it isn't a digit.
+func (s *SevSeg) bcdCode(pattern uint8) uint8 {
+	pattern &^= 0b10000000
+
+	for digit := range uint8(10) {
+		if s.getSegmentCode(digit) == pattern {
+			return digit
+		}
+	}
+
+	return bcdBlank
+}
+
+// decodable reports whether the decoder can show the character.
+func decodable(char byte) bool {
+	return char >= '0' && char <= '9' || char == ' ' || char == '.'
+}
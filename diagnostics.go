@@ -0,0 +1,138 @@
+package sevseg
+
+import (
+	"io"
+	"strconv"
+	"strings"
+)
+
+// defaultTestDelay is the time in milliseconds each segment is lit by the
+// "test" command of Diagnostics.
+const defaultTestDelay = 100
+
+// diagnosticsLineSize is the maximum length of a command line of Diagnostics.
+const diagnosticsLineSize = 64
+
+// Diagnostics answers text commands on a serial line, so support engineers
+// can query deployed units without reflashing them. Each command is a line
+// and is answered with one line:
+//
+//	dump           The current content, like FrameLiteral
+//	stats          The refresh rate, like GetFrameStats
+//	test [ms]      DisplayTest, lighting each segment for ms (100 by default)
+//
+// All other lines are passed to ProcessCommand, e.g. "num 42". Commands
+// without output are answered with "ok", failed ones with "error: " and the
+// error. Lines longer than 64 bytes are rejected with ErrLineTooLong.
+type Diagnostics struct {
+	display  *SevSeg
+	rw       io.ReadWriter
+	line     [diagnosticsLineSize]byte
+	length   uint8
+	overlong bool // Whether the current line exceeded the buffer
+	buf      [16]byte
+}
+
+// NewDiagnostics creates a responder for the display, reading commands from
+// and answering them on rw, e.g. machine.Serial. It starts the measurement
+// of GetFrameStats, so the "stats" command has values after a second.
+func NewDiagnostics(display *SevSeg, rw io.ReadWriter) *Diagnostics {
+	display.GetFrameStats()
+
+	return &Diagnostics{display: display, rw: rw}
+}
+
+// Poll reads the input available on the serial line and answers the complete
+// commands. Call it from the main loop; it only blocks if reading rw blocks,
+// which machine.Serial doesn't. The "test" command blocks until the test is
+// done and restores the content afterwards.
+//
+// Returns the error of reading or writing rw, e.g. io.EOF.
+func (d *Diagnostics) Poll() error {
+	n, err := d.rw.Read(d.buf[:])
+
+	for _, char := range d.buf[:n] {
+		switch char {
+		case '\r':
+		case '\n':
+			line := string(d.line[:d.length])
+			d.length = 0
+
+			if d.overlong {
+				d.overlong = false
+				if _, err := io.WriteString(d.rw, "error: "+ErrLineTooLong.Error()+"\n"); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if err := d.answer(line); err != nil {
+				return err
+			}
+		default:
+			// The rest of an overlong line is dropped
+			if d.length == diagnosticsLineSize {
+				d.overlong = true
+				continue
+			}
+
+			d.line[d.length] = char
+			d.length++
+		}
+	}
+
+	return err
+}
+
+// answer executes a command and writes the answer.
+func (d *Diagnostics) answer(line string) error {
+	command, argument, _ := strings.Cut(strings.TrimSpace(line), " ")
+	if command == "" {
+		return nil
+	}
+
+	var answer string
+	switch command {
+	case "dump":
+		answer = d.display.FrameLiteral()
+	case "stats":
+		stats := d.display.GetFrameStats()
+		answer = "frames/s " + strconv.FormatUint(uint64(stats.FrameRate), 10) +
+			" calls/s " + strconv.FormatUint(uint64(stats.CallRate), 10) +
+			" interval " + stats.MinInterval.String() + "-" + stats.MaxInterval.String()
+	case "test":
+		answer = d.test(strings.TrimSpace(argument))
+	default:
+		answer = "ok"
+		if err := d.display.ProcessCommand(line); err != nil {
+			answer = "error: " + err.Error()
+		}
+	}
+
+	_, err := io.WriteString(d.rw, answer+"\n")
+
+	return err
+}
+
+// test runs DisplayTest with the delay given in milliseconds, restoring the
+// content afterwards.
+func (d *Diagnostics) test(argument string) string {
+	delay := uint64(defaultTestDelay)
+	if argument != "" {
+		var err error
+		if delay, err = strconv.ParseUint(argument, 10, 16); err != nil {
+			return "error: " + ErrInvalidArgument.Error()
+		}
+	}
+
+	content := d.display.Snapshot()
+	d.display.DisplayTest(uint16(delay))
+
+	d.display.lock()
+	copy(d.display.updatedDisplay, content)
+	d.display.stageFrame()
+	d.display.unlock()
+
+	return "ok"
+}
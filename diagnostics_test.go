@@ -0,0 +1,49 @@
+//go:build !tinygo
+
+package sevseg
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// serialLine is a serial line with the input of the commands, recording the
+// answers.
+type serialLine struct {
+	input  strings.Reader
+	output bytes.Buffer
+}
+
+func (l *serialLine) Read(p []byte) (int, error) {
+	return l.input.Read(p)
+}
+
+func (l *serialLine) Write(p []byte) (int, error) {
+	return l.output.Write(p)
+}
+
+func TestDiagnosticsLineLimit(t *testing.T) {
+	s, err := NewSevSeg(Config{
+		DigitPins:   SimulatedPins(4),
+		SegmentPins: SimulatedPins(8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial := &serialLine{}
+	serial.input.Reset("num 12\n" + strings.Repeat("x", 1000) + "\nnum 34\n")
+	diagnostics := NewDiagnostics(s, serial)
+
+	for serial.input.Len() > 0 {
+		if err := diagnostics.Poll(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "ok\nerror: " + ErrLineTooLong.Error() + "\nok\n"
+	if got := serial.output.String(); got != want {
+		t.Errorf("answers %q, want %q", got, want)
+	}
+}
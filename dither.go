@@ -0,0 +1,21 @@
+package sevseg
+
+// ditheredLevel returns the number of lit steps of the next software PWM
+// period for the given brightness.
+//
+// The remainder of the brightness below the next duty level is accumulated
+// across periods (sigma-delta), and a period gets an additional lit step each
+// time the accumulated error exceeds a full level. This spreads the brighter
+// periods evenly, e.g. 13% with 10 steps lights 2 steps in every third period.
+func (s *SevSeg) ditheredLevel(brightness uint8) uint8 {
+	scaled := uint16(brightness) * uint16(s.pwmSteps) // In 1/100 steps
+	level := uint8(scaled / 100)
+
+	s.ditherError += uint8(scaled % 100)
+	if s.ditherError >= 100 {
+		s.ditherError -= 100
+		level++
+	}
+
+	return level
+}
@@ -0,0 +1,71 @@
+package sevseg
+
+// Driver receives the frames committed to a display, one segment pattern per
+// digit with the first element being the right most digit.
+//
+// The frame is only valid during the call of WriteFrame, drivers which keep
+// it must copy it.
+type Driver interface {
+	WriteFrame(frame []uint8)
+}
+
+// DriverFunc is an adapter to use an ordinary function as a Driver.
+type DriverFunc func(frame []uint8)
+
+// WriteFrame calls f(frame).
+func (f DriverFunc) WriteFrame(frame []uint8) {
+	f(frame)
+}
+
+// tee is a Driver forwarding every frame to two drivers.
+type tee struct {
+	primary   Driver
+	secondary Driver
+}
+
+// Tee returns a Driver which forwards every frame to both drivers, e.g. to the
+// real hardware and a recorder capturing what was displayed around the time
+// of a fault. If one of the drivers is nil, the other one is returned.
+func Tee(primary, secondary Driver) Driver {
+	if primary == nil {
+		return secondary
+	}
+
+	if secondary == nil {
+		return primary
+	}
+
+	return &tee{primary: primary, secondary: secondary}
+}
+
+// WriteFrame forwards the frame to both drivers.
+func (t *tee) WriteFrame(frame []uint8) {
+	t.primary.WriteFrame(frame)
+	t.secondary.WriteFrame(frame)
+}
+
+// SetMirror sets a driver which receives every frame committed to the
+// display, in addition to the display itself. Use Tee to mirror the content
+// to several drivers. Passing nil removes the mirror.
+func (s *SevSeg) SetMirror(driver Driver) {
+	s.lock()
+	defer s.unlock()
+
+	s.mirror = driver
+}
+
+// StreamFrames invokes w with every frame committed to the display, e.g. to
+// push the content to a network dashboard or to record a session for later
+// playback. It's added to the mirror set by SetMirror, so several streams can
+// be active at the same time and SetMirror(nil) removes all of them. The
+// frame is only valid during the call.
+func (s *SevSeg) StreamFrames(w func(frame []uint8)) {
+	s.lock()
+	defer s.unlock()
+
+	if w == nil {
+		return
+	}
+
+	s.mirror = Tee(s.mirror, DriverFunc(w))
+}
@@ -0,0 +1,51 @@
+package sevseg
+
+import "time"
+
+type durationFormat uint8
+
+// DurationMinutesSeconds shows a duration as MM.SS, DurationHoursMinutes as
+// HH.MM, both truncated to the shown unit.
+const (
+	DurationMinutesSeconds durationFormat = iota
+	DurationHoursMinutes
+)
+
+// SetDuration displays a duration for timers as MM.SS or HH.MM, depending on
+// the format. Both fields are zero padded to two digits, the leading field
+// grows to the left if needed, e.g. 125.00 for 125 minutes. The fields are
+// separated by the colon if the display has one, like SetClock, or the
+// decimal point otherwise.
+//
+// Returns ErrInvalidArgument for a negative duration or an unknown format,
+// ErrTooManyDigits if the duration doesn't fit or ErrNoDecimalPointPin if
+// the display has neither a colon nor a decimal point pin.
+func (s *SevSeg) SetDuration(d time.Duration, format durationFormat) error {
+	s.lock()
+	defer s.unlock()
+
+	if d < 0 || format > DurationHoursMinutes {
+		return ErrInvalidArgument
+	}
+
+	unit := time.Second
+	if format == DurationHoursMinutes {
+		unit = time.Minute
+	}
+
+	// A duration in minutes splits like one in seconds, into hours and
+	// minutes
+	total := d / unit
+	if total > 1<<32-1 {
+		return ErrTooManyDigits
+	}
+
+	fields := sexagesimalFields(uint32(total), 2)
+	if err := s.format.sexagesimal(s.updatedDisplay, fields[:2], 2, 2, s.hasColon()); err != nil {
+		return err
+	}
+
+	s.commitSeparated()
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package sevseg
+
+import "errors"
+
+// Errors returned by the display methods. They can be compared with
+// errors.Is, e.g. to print a meaningful diagnostic over UART.
+var (
+	// ErrInvalidConfig is returned by NewSevSeg for a configuration without
+	// digit pins or with fewer than 7 or more than 8 segment pins.
+	ErrInvalidConfig = errors.New("sevseg: invalid configuration")
+
+	// ErrInvalidArgument is returned for arguments outside of their valid
+	// range, e.g. a period of 0 ticks.
+	ErrInvalidArgument = errors.New("sevseg: invalid argument")
+
+	// ErrOutOfRange is returned for a digit, decimal point or LED position
+	// which doesn't exist on the display.
+	ErrOutOfRange = errors.New("sevseg: position out of range")
+
+	// ErrTooManyDigits is returned if a number or text doesn't fit the
+	// display.
+	ErrTooManyDigits = errors.New("sevseg: too many digits for the display")
+
+	// ErrUnsupportedChar is returned if a text contains a character which
+	// can't be displayed.
+	ErrUnsupportedChar = errors.New("sevseg: unsupported character")
+
+	// ErrNoDecimalPointPin is returned if a decimal point is required but the
+	// display has only 7 segment pins.
+	ErrNoDecimalPointPin = errors.New("sevseg: no decimal point pin")
+
+	// ErrNotConfigured is returned if a feature is used which hasn't been set
+	// up in the configuration, e.g. SetColon without ColonSegments.
+	ErrNotConfigured = errors.New("sevseg: feature not configured")
+
+	// ErrInvalidCalibration is returned if encoded calibration data is
+	// invalid, e.g. because the storage has never been written.
+	ErrInvalidCalibration = errors.New("sevseg: invalid calibration data")
+
+	// ErrUnknownProfile and ErrDuplicateProfile are returned for profile
+	// names which aren't or are already registered.
+	ErrUnknownProfile   = errors.New("sevseg: unknown profile")
+	ErrDuplicateProfile = errors.New("sevseg: profile already registered")
+
+	// ErrUnknownFont and ErrDuplicateFont are returned for font names which
+	// aren't or are already registered.
+	ErrUnknownFont   = errors.New("sevseg: unknown font")
+	ErrDuplicateFont = errors.New("sevseg: font already registered")
+
+	// ErrDisplayClaimed is returned by Claim if the display is held by a
+	// lease with the same or a higher priority.
+	ErrDisplayClaimed = errors.New("sevseg: display claimed")
+
+	// ErrUnknownCommand is returned by ProcessCommand for unknown commands.
+	ErrUnknownCommand = errors.New("sevseg: unknown command")
+
+	// ErrLineTooLong is answered by Diagnostics for command lines longer
+	// than 64 bytes.
+	ErrLineTooLong = errors.New("sevseg: command line too long")
+
+	// ErrReadbackMismatch is returned by WriteError if the display memory
+	// read back from a controller differs from what was written, e.g.
+	// because of corruption on a long I2C cable.
+	ErrReadbackMismatch = errors.New("sevseg: controller readback mismatch")
+)
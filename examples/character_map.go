@@ -0,0 +1,32 @@
+//go:build tinygo && arduino_nano
+
+// This script cycles through all glyphs the display can show.
+// Shows which characters can be displayed on a 7-segment display.
+//
+// This configures a 2-digit 7-segment Common Cathode display for arduino-nano
+
+package main
+
+import (
+	"time"
+
+	"github.com/domi413/sevseg"
+	"github.com/domi413/sevseg/presets"
+)
+
+func main() {
+	displayConfig := presets.NanoTwoDigitCC()
+
+	display, err := sevseg.NewSevSeg(displayConfig)
+	if err != nil {
+		return
+	}
+
+	// A frame of 2 digits takes 2ms, so each glyph is shown for 400ms
+	display.ShowCharacterMap(200)
+
+	for {
+		display.Refresh()
+		time.Sleep(1 * time.Millisecond)
+	}
+}
@@ -1,3 +1,5 @@
+//go:build tinygo && arduino_nano
+
 // This script displays a counting sequence from -9 to 99 on a 2-digit 7-segment display.
 // Demonstrates basic number display functionality.
 //
@@ -6,38 +8,22 @@
 package main
 
 import (
-	"machine"
 	"time"
 
 	"github.com/domi413/sevseg"
+	"github.com/domi413/sevseg/presets"
 )
 
 func main() {
-	displayConfig := sevseg.Config{
-		Hardware: sevseg.CommonCathode,
-		DigitPins: []machine.Pin{
-			machine.D3,
-			machine.D2,
-		},
-		SegmentPins: []machine.Pin{
-			machine.D4,  // A
-			machine.D5,  // B
-			machine.D6,  // C
-			machine.D7,  // D
-			machine.D8,  // E
-			machine.D9,  // F
-			machine.D10, // G
-			machine.D11, // DP
-		},
-		UseLeadingZeros: true,
-	}
+	displayConfig := presets.NanoTwoDigitCC()
+	displayConfig.UseLeadingZeros = true
 
-	display, ok := sevseg.NewSevSeg(displayConfig)
-	if !ok {
+	display, err := sevseg.NewSevSeg(displayConfig)
+	if err != nil {
 		return
 	}
 
-	counter := int8(-9)
+	counter := int32(-9)
 	refreshCounter := 0
 	refreshesPerUpdate := 200
 
@@ -1,3 +1,5 @@
+//go:build tinygo && arduino_nano
+
 // This script displays numbers with decimal points.
 //
 // This configures a 2-digit 7-segment Common Cathode display for arduino-nano
@@ -5,39 +7,22 @@
 package main
 
 import (
-	"machine"
 	"time"
 
 	"github.com/domi413/sevseg"
+	"github.com/domi413/sevseg/presets"
 )
 
 func main() {
-	displayConfig := sevseg.Config{
-		Hardware: sevseg.CommonCathode,
-		DigitPins: []machine.Pin{
-			machine.D3,
-			machine.D2,
-		},
-		SegmentPins: []machine.Pin{
-			machine.D4,  // A
-			machine.D5,  // B
-			machine.D6,  // C
-			machine.D7,  // D
-			machine.D8,  // E
-			machine.D9,  // F
-			machine.D10, // G
-			machine.D11, // DP
-		},
-		UseLeadingZeros: false,
-	}
+	displayConfig := presets.NanoTwoDigitCC()
 
-	display, ok := sevseg.NewSevSeg(displayConfig)
-	if !ok {
+	display, err := sevseg.NewSevSeg(displayConfig)
+	if err != nil {
 		return
 	}
 
 	examples := []struct {
-		number  int8
+		number  int32
 		decimal uint8
 	}{
 		{42, 0},
@@ -1,3 +1,5 @@
+//go:build tinygo && (pico || pico2)
+
 // This script shows the ability to scroll text on a 4-digit 7-segment display.
 //
 // This configures a 2-digit 7-segment Common Cathode display for raspberry pi pico 2
@@ -5,40 +7,21 @@
 package main
 
 import (
-	"machine"
 	"time"
 
 	"github.com/domi413/sevseg"
+	"github.com/domi413/sevseg/presets"
 )
 
 func main() {
-	displayConfig := sevseg.Config{
-		Hardware: sevseg.CommonCathode,
-		DigitPins: []machine.Pin{
-			machine.GP0,
-			machine.GP1,
-			machine.GP2,
-			machine.GP3,
-		},
-		SegmentPins: []machine.Pin{
-			machine.GP4,  // A
-			machine.GP5,  // B
-			machine.GP6,  // C
-			machine.GP7,  // D
-			machine.GP8,  // E
-			machine.GP9,  // F
-			machine.GP10, // G
-			machine.GP11, // DP
-		},
-		UseLeadingZeros: false,
-	}
+	displayConfig := presets.PicoFourDigitCC()
 
-	display, ok := sevseg.NewSevSeg(displayConfig)
-	if !ok {
+	display, err := sevseg.NewSevSeg(displayConfig)
+	if err != nil {
 		return
 	}
 
-	if !display.SetText("Crazy display test") {
+	if err := display.SetText("Crazy display test"); err != nil {
 		return
 	}
 
@@ -0,0 +1,124 @@
+package sevseg
+
+// Font holds the segment patterns of all characters the display can show,
+// indexed like characterMap: the digits 0-9, the letters A-Z, blank, minus,
+// decimal point, degree and underscore. Letters without a usable glyph, like
+// M and W, are 0.
+type Font [len(characterMap)]uint8
+
+// StandardFont is the built-in font, used unless another one is selected.
+var StandardFont = Font{
+	// GFEDCBA   Index   ASCII   Symbol   7-segment map:
+	0b00111111, // 0       0      '0'          AAA
+	0b0000110,  // 1       1      '1'         F   B
+	0b01011011, // 2       2      '2'         F   B
+	0b01001111, // 3       3      '3'          GGG
+	0b01100110, // 4       4      '4'         E   C
+	0b01101101, // 5       5      '5'         E   C
+	0b01111101, // 6       6      '6'          DDD
+	0b00000111, // 7       7      '7'
+	0b01111111, // 8       8      '8'
+	0b01101111, // 9       9      '9'
+
+	0b01110111, // 10     65      'A'
+	0b01111100, // 11     66      'b'
+	0b00111001, // 12     67      'C'
+	0b01011110, // 13     68      'd'
+	0b01111001, // 14     69      'E'
+	0b01110001, // 15     70      'F'
+	0b00111101, // 16     71      'G'
+	0b01110110, // 17     72      'H'
+	0b00110000, // 18     73      'I'
+	0b00001110, // 19     74      'J'
+	0b01110110, // 20     75      'K'  Same as 'H'
+	0b00111000, // 21     76      'L'
+	0b00000000, // 22     77      'M'  NO DISPLAY
+	0b01010100, // 23     78      'n'
+	0b00111111, // 24     79      'O'
+	0b01110011, // 25     80      'P'
+	0b01100111, // 26     81      'q'
+	0b01010000, // 27     82      'r'
+	0b01101101, // 28     83      'S'
+	0b01111000, // 29     84      't'
+	0b00111110, // 30     85      'U'
+	0b00111110, // 31     86      'V'  Same as 'U'
+	0b00000000, // 32     87      'W'  NO DISPLAY
+	0b01110110, // 33     88      'X'  Same as 'H'
+	0b01101110, // 34     89      'y'
+	0b01011011, // 35     90      'Z'  Same as '2'
+
+	0b00000000, // 36     32      ' '  BLANK
+	0b01000000, // 37     45      '-'  DASH / MINUS
+	0b10000000, // 38     46      '.'  PERIOD / DECIMAL POINT
+	0b01100011, // 39     42      '°'  DEGREE
+	0b00001000, // 40     95      '_'  UNDERSCORE
+}
+
+// namedFont is a font registered with RegisterFont.
+type namedFont struct {
+	name string
+	font *Font
+}
+
+var (
+	fonts       []namedFont     // All registered fonts
+	defaultFont = &StandardFont // Font of displays without Config.FontName
+)
+
+// RegisterFont registers a font under a name, so displays can select it with
+// Config.FontName instead of passing the table around, e.g. when one binary
+// hosts several displays with different glyph styles. The font is
+// referenced, not copied.
+//
+// Returns ErrInvalidArgument if the name is empty or font is nil, or
+// ErrDuplicateFont if the name is already registered.
+func RegisterFont(name string, font *Font) error {
+	if name == "" || font == nil {
+		return ErrInvalidArgument
+	}
+
+	if _, ok := lookupFont(name); ok {
+		return ErrDuplicateFont
+	}
+
+	fonts = append(fonts, namedFont{name: name, font: font})
+
+	return nil
+}
+
+// SetDefaultFont selects the registered font used by displays created
+// afterwards without a Config.FontName. An empty name restores StandardFont.
+// Displays which already exist keep their font.
+//
+// Returns ErrUnknownFont if no such font has been registered.
+func SetDefaultFont(name string) error {
+	if name == "" {
+		defaultFont = &StandardFont
+		return nil
+	}
+
+	font, ok := lookupFont(name)
+	if !ok {
+		return ErrUnknownFont
+	}
+
+	defaultFont = font
+
+	return nil
+}
+
+// lookupFont returns the font registered under the given name, or the
+// default font for an empty name.
+func lookupFont(name string) (*Font, bool) {
+	if name == "" {
+		return defaultFont, true
+	}
+
+	for _, f := range fonts {
+		if f.name == name {
+			return f.font, true
+		}
+	}
+
+	return nil, false
+}
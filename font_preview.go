@@ -0,0 +1,90 @@
+package sevseg
+
+import "io"
+
+// PrintFont writes every character the display supports as ASCII art to w,
+// including aliases set with AliasChar and AliasGlyph. This helps designing
+// custom glyphs, e.g. by printing the whole character set over a serial
+// console:
+//
+//	display.PrintFont(machine.Serial)
+//
+// Lower-case letters are shown as their upper-case counterparts and omitted.
+func (s *SevSeg) PrintFont(w io.Writer) error {
+	s.lock()
+	defer s.unlock()
+
+	const glyphsPerRow = 8
+
+	var chars []byte
+	for char := byte(' '); char <= '~'; char++ {
+		if upperCase(char) != char {
+			continue
+		}
+
+		if _, ok := s.format.appendGlyph(nil, char); ok {
+			chars = append(chars, char)
+		}
+	}
+
+	for start := 0; start < len(chars); start += glyphsPerRow {
+		row := chars[start:min(start+glyphsPerRow, len(chars))]
+		if _, err := w.Write(s.fontRow(row)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fontRow renders the characters side by side as four lines of ASCII art,
+// the last one labeling each character:
+//
+//	 _
+//	|_|
+//	|_|.
+//	 8
+func (s *SevSeg) fontRow(chars []byte) []byte {
+	var lines [4][]byte
+
+	for _, char := range chars {
+		patterns, _ := s.format.appendGlyph(nil, char)
+		for _, pattern := range patterns {
+			appendSegmentArt(lines[:3], pattern)
+		}
+
+		lines[3] = append(lines[3], ' ', char)
+		for range 4*len(patterns) - 2 {
+			lines[3] = append(lines[3], ' ')
+		}
+
+		for i := range lines {
+			lines[i] = append(lines[i], ' ')
+		}
+	}
+
+	var out []byte
+	for _, line := range lines {
+		out = append(out, line...)
+		out = append(out, '\n')
+	}
+
+	return append(out, '\n')
+}
+
+// appendSegmentArt appends the pattern as ASCII art to the three lines.
+func appendSegmentArt(lines [][]byte, pattern uint8) {
+	lines[0] = append(lines[0], ' ', segmentChar(pattern, 0, '_'), ' ', ' ')
+	lines[1] = append(lines[1], segmentChar(pattern, 5, '|'), segmentChar(pattern, 6, '_'), segmentChar(pattern, 1, '|'), ' ')
+	lines[2] = append(lines[2], segmentChar(pattern, 4, '|'), segmentChar(pattern, 3, '_'), segmentChar(pattern, 2, '|'), segmentChar(pattern, 7, '.'))
+}
+
+// segmentChar returns c if the segment (0 being A, 7 DP) is lit in the
+// pattern and a space otherwise.
+func segmentChar(pattern uint8, segment uint8, c byte) byte {
+	if pattern&(1<<segment) == 0 {
+		return ' '
+	}
+
+	return c
+}
@@ -0,0 +1,405 @@
+package sevseg
+
+// Formatter converts numbers and text to segment patterns, independent of any
+// hardware. The display uses it for all its content, so tests, simulators or
+// other displays, e.g. an LCD emulation or a GUI widget, can reuse the exact
+// same formatting:
+//
+//	f := sevseg.Formatter{DecimalPoint: true}
+//	frame := make([]uint8, 4)
+//	f.Decimals(frame, 1234, []uint8{2}) // "12.34"
+//
+// The patterns are written right-aligned into a destination slice, the first
+// element being the right most digit, in the format of SetSegment. The zero
+// value formats like a display with 7 segments and the default settings.
+type Formatter struct {
+	// UseLeadingZeros, SignPlacement and ExpandWideLetters are the settings
+	// of the same name in Config.
+	UseLeadingZeros   bool
+	SignPlacement     signPlacement
+	ExpandWideLetters bool
+
+	// DecimalPoint defines whether the digits have a decimal point. Without
+	// it, formats requiring one return ErrNoDecimalPointPin.
+	DecimalPoint bool
+
+	// Font defines the glyphs of all characters, nil for StandardFont.
+	Font *Font
+
+	// Numerals defines the glyphs of the digits 0-9, overriding those of the
+	// Font; nil for the ones of the Font.
+	Numerals *Numerals
+
+	bcdOnly     bool         // Only characters a BCD decoder can show
+	signElement bool         // Whether a dedicated sign element, lit by the caller, shows the minus
+	aliases     []glyphAlias // Characters rendered with other glyphs
+}
+
+// Glyph returns the segment pattern of a character and whether it can be
+// shown. Lower-case letters are shown like upper-case ones. Aliases aren't
+// taken into account, as they may span several digits; use Patterns for them.
+func (f *Formatter) Glyph(char byte) (uint8, bool) {
+	if char >= 'a' && char <= 'z' {
+		// Since we can't differ between upper and lower case letters, we
+		// convert lower-case letters to upper-case.
+		char = char - 'a' + 'A'
+	}
+
+	if f.bcdOnly && !decodable(char) {
+		return 0, false
+	}
+
+	switch {
+	case char >= '0' && char <= '9':
+		return f.code(char - '0'), true
+	case char >= 'A' && char <= 'Z':
+		return f.code(char - 'A' + 10), true
+	case char == ' ':
+		return f.code(36), true
+	case char == '-':
+		return f.code(37), true
+	case char == '.':
+		return f.code(38), f.DecimalPoint
+	case char == '*':
+		return f.code(39), true
+	case char == '_':
+		return f.code(40), true
+	}
+
+	return 0, false
+}
+
+// Number writes the signed number right-aligned into dst.
+//
+// Returns ErrTooManyDigits and leaves dst untouched if the number doesn't fit.
+func (f *Formatter) Number(dst []uint8, number int32) error {
+	isNegative := number < 0
+
+	magnitude := uint32(number)
+	if isNegative {
+		magnitude = -magnitude
+	}
+
+	return f.Digits(dst, magnitude, isNegative, 10, 1)
+}
+
+// Hex writes the number in hexadecimal right-aligned into dst.
+//
+// Returns ErrTooManyDigits and leaves dst untouched if the number doesn't fit.
+func (f *Formatter) Hex(dst []uint8, number uint32) error {
+	return f.Digits(dst, number, false, 16, 1)
+}
+
+// Decimals writes the number right-aligned into dst and adds decimal points
+// at the given positions, zero indexed from the right.
+//
+// Returns ErrNoDecimalPointPin without DecimalPoint, ErrInvalidArgument if no
+// positions are given, ErrOutOfRange if a position is outside of dst or
+// ErrTooManyDigits if the number doesn't fit.
+func (f *Formatter) Decimals(dst []uint8, number int32, decimalPointsPositions []uint8) error {
+	if !f.DecimalPoint {
+		return ErrNoDecimalPointPin
+	}
+
+	if len(decimalPointsPositions) == 0 {
+		return ErrInvalidArgument
+	}
+
+	for _, decimalPos := range decimalPointsPositions {
+		if decimalPos >= uint8(len(dst)) {
+			return ErrOutOfRange
+		}
+	}
+
+	if err := f.Number(dst, number); err != nil {
+		return err
+	}
+
+	for _, decimalPos := range decimalPointsPositions {
+		dst[decimalPos] |= f.code(38) // DECIMAL POINT
+	}
+
+	return nil
+}
+
+// Temperature writes the temperature followed by a ° character right-aligned
+// into dst. Without DecimalPoint, it's rounded down to whole degrees.
+//
+// Returns ErrTooManyDigits if the temperature doesn't fit.
+func (f *Formatter) Temperature(dst []uint8, temperature float32, decimalPlaces uint8) error {
+	_, err := f.temperature(dst, temperature, decimalPlaces)
+
+	return err
+}
+
+// temperature is Temperature, also returning whether the temperature shown is
+// negative, e.g. to light a sign element.
+func (f *Formatter) temperature(dst []uint8, temperature float32, decimalPlaces uint8) (bool, error) {
+	if len(dst) <= 1 {
+		return false, ErrTooManyDigits // We need at least 2 digits to display a number
+	}
+
+	// Degrade to whole degrees without the decimal point
+	if !f.DecimalPoint {
+		decimalPlaces = 0
+	}
+
+	scale := int32(1)
+	for range decimalPlaces + 1 { // Additional *10 for the ° Character
+		scale *= 10
+	}
+
+	scaled := int32(temperature * float32(scale))
+
+	var err error
+	if decimalPlaces > 0 {
+		// Scale temperature by 10 to reserve space for ° symbol
+		err = f.Decimals(dst, scaled, []uint8{decimalPlaces + 1})
+	} else {
+		err = f.Number(dst, scaled)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	dst[0] = f.code(39) // DEGREE
+
+	return scaled < 0, nil
+}
+
+// sexagesimalRadices are the number of seconds per minute, minutes per hour
+// and hours per day.
+var sexagesimalRadices = [...]uint32{60, 60, 24}
+
+// sexagesimalFields splits a duration into the given number (1-4) of fields,
+// its seconds, minutes, hours and days, the least significant field first.
+// The last field holds the rest, e.g. the total minutes for two fields. The
+// unused fields are zero.
+func sexagesimalFields(totalSeconds uint32, count int) [4]uint32 {
+	var fields [4]uint32
+	for i := range count - 1 {
+		fields[i] = totalSeconds % sexagesimalRadices[i]
+		totalSeconds /= sexagesimalRadices[i]
+	}
+	fields[count-1] = totalSeconds
+
+	return fields
+}
+
+// Sexagesimal writes a duration as days, hours, minutes and seconds
+// right-aligned into dst, separated by decimal points, e.g. "1.02.03" for an
+// hour, 2 minutes and 3 seconds. Leading fields which are zero are omitted.
+//
+// Returns ErrTooManyDigits if the duration doesn't fit or
+// ErrNoDecimalPointPin if a separator is needed without DecimalPoint.
+func (f *Formatter) Sexagesimal(dst []uint8, totalSeconds uint32) error {
+	fields := sexagesimalFields(totalSeconds, 4)
+
+	return f.sexagesimal(dst, fields[:], 1, 1, false)
+}
+
+// sexagesimal writes the fields, the least significant field first,
+// right-aligned into dst, separated by the decimal point, or not at all if
+// the caller lights a colon between them. Leading fields which are zero are
+// omitted, but at least minFields are shown. The first field shown is zero
+// padded to leadingDigits, all other fields to two digits.
+//
+// Returns an error and leaves dst untouched if the fields don't fit or a
+// separator is needed but there is no decimal point pin.
+func (f *Formatter) sexagesimal(dst []uint8, fields []uint32, minFields int, leadingDigits uint8, colon bool) error {
+	count := max(minFields, 1)
+	for i := range fields {
+		if fields[i] > 0 {
+			count = max(count, i+1)
+		}
+	}
+	count = min(count, len(fields))
+
+	if count > 1 && !colon && !f.DecimalPoint {
+		return ErrNoDecimalPointPin
+	}
+
+	if 2*(count-1)+max(int(leadingDigits), decimalDigits(fields[count-1])) > len(dst) {
+		return ErrTooManyDigits
+	}
+
+	position := 0
+	for i := range count - 1 {
+		f.Digits(dst[position:position+2], fields[i], false, 10, 2)
+		position += 2
+	}
+
+	f.Digits(dst[position:], fields[count-1], false, 10, leadingDigits)
+
+	if colon {
+		return nil
+	}
+
+	for i := 1; i < count; i++ {
+		dst[2*i] |= f.code(38) // DECIMAL POINT
+	}
+
+	return nil
+}
+
+// decimalDigits returns the number of decimal digits of n, at least 1.
+func decimalDigits(n uint32) int {
+	digits := 1
+	for n /= 10; n > 0; n /= 10 {
+		digits++
+	}
+
+	return digits
+}
+
+// Text writes the text left-aligned into dst, the remaining digits (on the
+// right) are cleared.
+//
+// Returns ErrTooManyDigits if the text is longer than dst, or the error of
+// Patterns.
+func (f *Formatter) Text(dst []uint8, text string) error {
+	patterns, err := f.Patterns(text)
+	if err != nil {
+		return err
+	}
+
+	if len(patterns) > len(dst) {
+		return ErrTooManyDigits
+	}
+
+	for i := range dst {
+		dst[i] = f.code(36) // BLANK
+	}
+
+	for i, pattern := range patterns {
+		dst[len(dst)-1-i] = pattern
+	}
+
+	return nil
+}
+
+// Digits writes the number in the given base right-aligned into dst, the
+// first element being the right most digit. The number is zero padded to at
+// least minDigits digits and prefixed with a minus sign if isNegative is set,
+// placed according to SignPlacement. The remaining digits are blank or zero,
+// depending on UseLeadingZeros.
+//
+// Returns ErrTooManyDigits and leaves dst untouched if the number doesn't fit.
+func (f *Formatter) Digits(dst []uint8, number uint32, isNegative bool, base uint32, minDigits uint8) error {
+	// The minus takes a digit of its own, unless there is a sign element
+	return f.padded(dst, number, isNegative && !f.signElement, base, minDigits, f.UseLeadingZeros)
+}
+
+// padded is Digits with the minus on a digit if minus is set and
+// the remaining digits padded with zeros if zeros is set.
+func (f *Formatter) padded(dst []uint8, number uint32, minus bool, base uint32, minDigits uint8, zeros bool) error {
+	minDigits = max(minDigits, 1)
+
+	count := uint8(0)
+	for n := number; n > 0; n /= base {
+		count++
+	}
+	count = max(count, minDigits)
+
+	if minus {
+		count++
+	}
+
+	if int(count) > len(dst) {
+		return ErrTooManyDigits
+	}
+
+	initPattern := f.code(36) // BLANK
+	if zeros {
+		initPattern = f.code(0) // ZERO
+	}
+
+	for i := range dst {
+		if i < int(minDigits) {
+			dst[i] = f.code(0) // ZERO
+		} else {
+			dst[i] = initPattern
+		}
+	}
+
+	position := 0
+	for ; number > 0; number /= base {
+		dst[position] = f.code(uint8(number % base))
+		position++
+	}
+
+	if minus {
+		signPosition := max(position, int(minDigits))
+		if f.SignPlacement == SignLeftmost || (f.SignPlacement == SignAuto && zeros) {
+			signPosition = len(dst) - 1
+		}
+
+		dst[signPosition] = f.code(37) // MINUS
+	}
+
+	return nil
+}
+
+// code returns the segment code for a given index of the font.
+func (f *Formatter) code(index uint8) uint8 {
+	if f.Numerals != nil && index < uint8(len(f.Numerals)) {
+		return f.Numerals[index]
+	}
+
+	font := f.Font
+	if font == nil {
+		font = &StandardFont
+	}
+
+	return font[index]
+}
+
+// appendGlyph appends the segment patterns of a character to dst, taking the
+// aliases and the expansion of wide letters into account.
+func (f *Formatter) appendGlyph(dst []uint8, char byte) ([]uint8, bool) {
+	upper := upperCase(char)
+	for _, alias := range f.aliases {
+		if alias.char == upper {
+			return append(dst, alias.patterns...), true
+		}
+	}
+
+	if f.ExpandWideLetters {
+		for _, letter := range wideLetters {
+			if letter.char == upper {
+				return append(dst, letter.patterns...), true
+			}
+		}
+	}
+
+	pattern, ok := f.Glyph(char)
+	if !ok {
+		return dst, false
+	}
+
+	return append(dst, pattern), true
+}
+
+// Patterns converts a text to its segment patterns in reading order, taking
+// the aliases and ExpandWideLetters into account. Aliased characters may take
+// several digits, so the result can be longer than the text.
+//
+// Returns ErrNoDecimalPointPin for a '.' without DecimalPoint, or
+// ErrUnsupportedChar for other characters which can't be shown.
+func (f *Formatter) Patterns(text string) ([]uint8, error) {
+	patterns := make([]uint8, 0, len(text))
+
+	for _, char := range []byte(text) {
+		var ok bool
+		if patterns, ok = f.appendGlyph(patterns, char); !ok {
+			if char == '.' {
+				return nil, ErrNoDecimalPointPin
+			}
+
+			return nil, ErrUnsupportedChar
+		}
+	}
+
+	return patterns, nil
+}
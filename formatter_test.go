@@ -0,0 +1,155 @@
+package sevseg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var signPlacements = map[signPlacement]string{
+	SignAuto:     "SignAuto",
+	SignLeftmost: "SignLeftmost",
+	SignAdjacent: "SignAdjacent",
+}
+
+// signedText returns the expected text of a number with the given digits on
+// a display of width digits, following the placement table of SignAuto, or
+// "" if it doesn't fit.
+func signedText(placement signPlacement, zeros bool, width int, digits string, negative bool) string {
+	length := len(digits)
+	if negative {
+		length++
+	}
+
+	if length > width {
+		return ""
+	}
+
+	pad := " "
+	if zeros {
+		pad = "0"
+	}
+
+	padding := strings.Repeat(pad, width-length)
+	if !negative {
+		return padding + digits
+	}
+
+	if placement == SignLeftmost || (placement == SignAuto && zeros) {
+		return "-" + padding + digits
+	}
+
+	return padding + "-" + digits
+}
+
+// textFrame converts text to a frame, the first element being the right most
+// digit.
+func textFrame(t *testing.T, f *Formatter, text string) []uint8 {
+	t.Helper()
+
+	frame := make([]uint8, len(text))
+	for i := range len(text) {
+		pattern, ok := f.Glyph(text[len(text)-1-i])
+		if !ok {
+			t.Fatalf("no glyph for %q", text[len(text)-1-i])
+		}
+
+		frame[i] = pattern
+	}
+
+	return frame
+}
+
+func TestSignPlacementTable(t *testing.T) {
+	tests := []struct {
+		want  [2]string // Without and with leading zeros
+		place signPlacement
+	}{
+		{[2]string{"  -5", "-005"}, SignAuto},
+		{[2]string{"-  5", "-005"}, SignLeftmost},
+		{[2]string{"  -5", "00-5"}, SignAdjacent},
+	}
+
+	for _, tt := range tests {
+		for i, zeros := range []bool{false, true} {
+			f := Formatter{SignPlacement: tt.place, UseLeadingZeros: zeros}
+			dst := make([]uint8, 4)
+
+			if err := f.Number(dst, -5); err != nil {
+				t.Fatalf("%s zeros=%t: %v", signPlacements[tt.place], zeros, err)
+			}
+
+			if want := textFrame(t, &f, tt.want[i]); string(dst) != string(want) {
+				t.Errorf("%s zeros=%t: got %v, want %q", signPlacements[tt.place], zeros, dst, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestSignPlacementWidths(t *testing.T) {
+	numbers := []struct {
+		number   uint32
+		negative bool
+		digits   string
+	}{
+		{0, false, "0"},
+		{0, true, "0"}, // -0
+		{5, true, "5"},
+		{42, false, "42"},
+		{42, true, "42"},
+		{1234, true, "1234"},
+		{1234567, false, "1234567"},
+		{1234567, true, "1234567"},
+		{12345678, false, "12345678"},
+		{12345678, true, "12345678"},
+		{123456789, false, "123456789"},
+	}
+
+	const untouched = 0xA5
+
+	for place, name := range signPlacements {
+		for _, zeros := range []bool{false, true} {
+			f := Formatter{SignPlacement: place, UseLeadingZeros: zeros}
+
+			for width := 1; width <= 8; width++ {
+				for _, n := range numbers {
+					dst := make([]uint8, width)
+					for i := range dst {
+						dst[i] = untouched
+					}
+
+					err := f.Digits(dst, n.number, n.negative, 10, 1)
+					want := signedText(place, zeros, width, n.digits, n.negative)
+
+					sign := ""
+					if n.negative {
+						sign = "-"
+					}
+
+					if want == "" {
+						if !errors.Is(err, ErrTooManyDigits) {
+							t.Errorf("%s zeros=%t width %d %s%s: err = %v, want ErrTooManyDigits", name, zeros, width, sign, n.digits, err)
+						}
+
+						for i, pattern := range dst {
+							if pattern != untouched {
+								t.Errorf("%s zeros=%t width %d %s%s: digit %d written on overflow", name, zeros, width, sign, n.digits, i)
+							}
+						}
+
+						continue
+					}
+
+					if err != nil {
+						t.Errorf("%s zeros=%t width %d %s%s: %v", name, zeros, width, sign, n.digits, err)
+						continue
+					}
+
+					if expected := textFrame(t, &f, want); string(dst) != string(expected) {
+						t.Errorf("%s zeros=%t width %d %s%s: got %v, want %q", name, zeros, width, sign, n.digits, dst, want)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package sevseg
+
+// The digits set by the setters are triple buffered, so Refresh never shows
+// them half way through an update, even if it runs from a timer interrupt
+// (see StartAutoRefresh). The setters write updatedDisplay, the back buffer,
+// and commit copies it to stagedDisplay. At the start of the next frame, i.e.
+// when the multiplexing wraps around to the first digit, Refresh applies the
+// staged content to liveDisplay, the buffer it reads the digits from. The
+// staged flag is cleared while commit copies, so Refresh never applies a half
+// copied buffer.
+//
+// Only these digits are staged. The overlays drawn over them, i.e. the
+// warning, the alternate content, the animated digits and the crossfade, as
+// well as the annotations and the indicators, are read by Refresh directly
+// and change as soon as they are set, possibly in the middle of a frame.
+// The auto refresh goroutine locks the display, but the timer interrupt
+// doesn't: it may show an overlay half way through an update for one digit,
+// but never reads past its content, e.g. ClearWarning hides the warning
+// before dropping its content.
+//
+// Controllers don't multiplex in Refresh and receive every committed frame
+// right away, so the live buffer is the back buffer for them.
+
+// stageFrame hands the back buffer over to Refresh.
+func (s *SevSeg) stageFrame() {
+	if s.backend != nil {
+		return
+	}
+
+	s.staged.Store(false)
+	copy(s.stagedDisplay, s.updatedDisplay)
+	s.staged.Store(true)
+}
+
+// applyStagedFrame makes the staged content live. It's called at the start of
+// a frame.
+func (s *SevSeg) applyStagedFrame() {
+	if s.staged.CompareAndSwap(true, false) {
+		s.startCrossfade()
+		copy(s.liveDisplay, s.stagedDisplay)
+	}
+}
@@ -0,0 +1,78 @@
+package sevseg_test
+
+import (
+	"testing"
+
+	"github.com/domi413/sevseg"
+	"github.com/domi413/sevseg/sevsegtest"
+)
+
+// litDigit returns the digit lit by the last Refresh call and its pattern,
+// or -1 if no digit was lit.
+func litDigit(rec *sevsegtest.Recorder, cfg sevseg.Config) (int, uint8) {
+	for digit, pattern := range rec.Lit(cfg) {
+		if pattern != 0 {
+			return digit, pattern
+		}
+	}
+
+	return -1, 0
+}
+
+func TestContentChangesBetweenFrames(t *testing.T) {
+	for _, brightness := range []uint8{100, 70, 30, 5} {
+		for offset := range 40 {
+			rec := sevsegtest.NewRecorder()
+			cfg := sevseg.Config{
+				DigitPins:   rec.NewPins("D", 4),
+				SegmentPins: rec.NewPins("S", 8),
+			}
+
+			display, err := sevseg.NewSevSeg(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			display.SetBrightness(brightness)
+			display.SetNumber(1111)
+
+			// A frame lists the patterns of the digits in scan order, it
+			// ends when the first digit scanned is lit again
+			var frames [][]uint8
+			first := -1
+			for call := range 400 {
+				if call == offset {
+					display.SetNumber(2222)
+				}
+
+				rec.Reset()
+				display.Refresh()
+
+				digit, pattern := litDigit(rec, cfg)
+				if digit < 0 {
+					continue
+				}
+
+				if first < 0 {
+					first = digit
+				}
+
+				if digit == first {
+					frames = append(frames, nil)
+				}
+				frames[len(frames)-1] = append(frames[len(frames)-1], pattern)
+			}
+
+			for i, frame := range frames {
+				for _, pattern := range frame {
+					if pattern != frame[0] {
+						t.Fatalf("brightness %d, change after %d calls: frame %d mixes contents %07b", brightness, offset, i, frame)
+					}
+				}
+			}
+
+			if last := frames[len(frames)-1]; last[0] != 0b1011011 {
+				t.Errorf("brightness %d, change after %d calls: last frame %07b, want 2222", brightness, offset, last)
+			}
+		}
+	}
+}
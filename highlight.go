@@ -0,0 +1,66 @@
+package sevseg
+
+// SetChangeHighlight makes the display blink whenever SetNumber sets a number
+// which differs from the previous one by more than threshold, so anomalies
+// stand out on monitoring panels. The display is turned off and on again blinks
+// times, each phase lasting periodTicks ticks of the effects clock, i.e. full
+// frames of Refresh. A blink count of 0 disables the highlight.
+//
+// Returns ErrInvalidArgument if periodTicks is 0 while blinks isn't.
+func (s *SevSeg) SetChangeHighlight(threshold uint32, blinks uint8, periodTicks uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if blinks > 0 && periodTicks == 0 {
+		return ErrInvalidArgument
+	}
+
+	s.highlightThreshold = threshold
+	s.highlightBlinks = blinks
+	s.highlightPeriod = periodTicks
+	s.highlightPhases = 0
+
+	return nil
+}
+
+// highlightChange starts blinking if number differs from the previously set
+// number by more than the threshold.
+func (s *SevSeg) highlightChange(number int32) {
+	previous, known := s.lastNumber, s.lastNumberSet
+	s.lastNumber, s.lastNumberSet = number, true
+
+	if s.highlightBlinks == 0 || !known {
+		return
+	}
+
+	diff := int64(number) - int64(previous)
+	if diff < 0 {
+		diff = -diff
+	}
+
+	if diff > int64(s.highlightThreshold) {
+		s.highlightPhases = 2 * uint16(s.highlightBlinks)
+		s.highlightNext = s.deadline(s.highlightPeriod)
+	}
+}
+
+// advanceHighlight advances the blinking by one tick of the effects clock.
+func (s *SevSeg) advanceHighlight() {
+	if s.highlightPhases == 0 {
+		return
+	}
+
+	if s.expired(&s.highlightNext, s.highlightPeriod) {
+		s.highlightPhases--
+		s.invalidate()
+	}
+}
+
+// shown reports whether the content is visible, i.e. it isn't hidden by
+// Toggle or by the off phase of a change highlight. Each blink starts with
+// the off phase.
+func (s *SevSeg) shown() bool {
+	hidden := s.highlightPhases > 0 && s.highlightPhases%2 == 0
+
+	return s.visible && !hidden
+}
@@ -0,0 +1,43 @@
+package sevseg
+
+// GetHistory returns copies of the last committed frames, oldest first, to
+// show what was on the display when a fault occurred. Every successful call of
+// a method changing the content commits a frame. The number of frames kept is
+// set by Config.HistoryDepth; without it, the history is always empty.
+func (s *SevSeg) GetHistory() [][]uint8 {
+	s.lock()
+	defer s.unlock()
+
+	digits := len(s.updatedDisplay)
+	depth := uint8(0)
+	if digits > 0 {
+		depth = uint8(len(s.history) / digits)
+	}
+
+	frames := make([][]uint8, s.historyCount)
+	for i := range s.historyCount {
+		// The oldest frame is historyCount entries before the next one
+		index := (s.historyNext + depth - s.historyCount + i) % depth
+		frames[i] = make([]uint8, digits)
+		copy(frames[i], s.history[int(index)*digits:])
+	}
+
+	return frames
+}
+
+// recordHistory stores the current frame in the history ring buffer,
+// overwriting the oldest frame if it's full.
+func (s *SevSeg) recordHistory() {
+	digits := len(s.updatedDisplay)
+	if len(s.history) == 0 || digits == 0 {
+		return
+	}
+
+	depth := uint8(len(s.history) / digits)
+	copy(s.history[int(s.historyNext)*digits:], s.updatedDisplay)
+
+	s.historyNext = (s.historyNext + 1) % depth
+	if s.historyCount < depth {
+		s.historyCount++
+	}
+}
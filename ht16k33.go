@@ -0,0 +1,144 @@
+package sevseg
+
+import "bytes"
+
+const (
+	ht16k33DefaultAddress = 0x70
+	ht16k33OscillatorOn   = 0x21
+	ht16k33DisplayOff     = 0x80
+	ht16k33DisplayOn      = 0x81
+	ht16k33Dimming        = 0xE0 // Or'ed with the level (0-15)
+
+	// ht16k33ColonRow is the row of the display memory which holds the colon
+	// on Adafruit-style backpacks, the digits skip it.
+	ht16k33ColonRow = 2
+)
+
+// I2C is an I2C bus of the board, e.g. machine.I2C0. The TinyGo machine
+// package has a different type for each board, all of which implement this
+// interface.
+type I2C interface {
+	Tx(addr uint16, w, r []byte) error
+}
+
+// ht16k33 is the backend for the HT16K33 controller of Adafruit-style
+// backpacks. The display memory has a 16-bit row per digit, of which only the
+// low byte is used by 7-segment displays. Frames and brightness are only sent
+// if they changed.
+type ht16k33 struct {
+	bus     I2C
+	address uint16
+	frame   []uint8 // Last frame sent
+	level   uint8   // Last dimming level sent, 0xFF if the display is off
+	sent    bool
+	buf     [1 + 2*8]byte // Start address followed by the rows
+
+	// Write verification state, err is the first failure since writeError
+	verify   bool
+	err      error
+	readback [2 * 8]byte
+}
+
+// newHT16K33 creates an HT16K33 backend driving the given number of digits.
+func newHT16K33(bus I2C, address uint16, digits uint8) *ht16k33 {
+	if address == 0 {
+		address = ht16k33DefaultAddress
+	}
+
+	h := &ht16k33{
+		bus:     bus,
+		address: address,
+		frame:   make([]uint8, digits),
+		level:   0xFF,
+	}
+
+	h.command(ht16k33OscillatorOn)
+	h.command(ht16k33DisplayOff)
+
+	return h
+}
+
+// writeFrame writes the frame to the display memory, the left most digit to
+// the first row.
+func (h *ht16k33) writeFrame(frame []uint8) {
+	if h.sent && bytes.Equal(frame, h.frame) {
+		return
+	}
+
+	copy(h.frame, frame)
+	h.sent = true
+
+	clear(h.buf[:])
+	for i := range frame {
+		row := len(frame) - 1 - i
+		if row >= ht16k33ColonRow {
+			row++
+		}
+
+		h.buf[1+2*row] = frame[i]
+	}
+
+	h.bus.Tx(h.address, h.buf[:], nil)
+
+	if h.verify {
+		h.verifyFrame()
+	}
+}
+
+// verifyFrame reads back the display memory and writes the frame again if it
+// differs, recording the failure.
+func (h *ht16k33) verifyFrame() {
+	// The start address (0) sets the address pointer for reading
+	err := h.bus.Tx(h.address, h.buf[:1], h.readback[:])
+	if err == nil && !bytes.Equal(h.readback[:], h.buf[1:]) {
+		err = ErrReadbackMismatch
+		h.bus.Tx(h.address, h.buf[:], nil)
+	}
+
+	if h.err == nil {
+		h.err = err
+	}
+}
+
+// setVerify turns reading back every written frame on or off.
+func (h *ht16k33) setVerify(on bool) {
+	h.verify = on
+}
+
+// writeError returns and clears the first failure since the last call.
+func (h *ht16k33) writeError() error {
+	err := h.err
+	h.err = nil
+
+	return err
+}
+
+// setBrightness maps the brightness to the 16 dimming levels of the
+// controller, 0 turns the display off.
+func (h *ht16k33) setBrightness(brightness uint8) {
+	level := uint8(0xFF)
+	if brightness > 0 {
+		level = uint8((uint16(brightness) - 1) * 16 / 100)
+	}
+
+	if level == h.level {
+		return
+	}
+
+	if level == 0xFF {
+		h.command(ht16k33DisplayOff)
+	} else {
+		h.command(ht16k33Dimming | level)
+		if h.level == 0xFF {
+			h.command(ht16k33DisplayOn)
+		}
+	}
+
+	h.level = level
+}
+
+// command sends a single byte command to the controller.
+func (h *ht16k33) command(command uint8) {
+	h.buf[0] = command
+	h.bus.Tx(h.address, h.buf[:1], nil)
+}
@@ -0,0 +1,87 @@
+package sevseg
+
+import (
+	"bytes"
+	"testing"
+)
+
+// fakeI2C is an HT16K33 on an I2C bus, recording the commands and keeping
+// the display memory so it can be read back.
+type fakeI2C struct {
+	dimming uint8
+	on      bool
+	memory  [16]byte
+
+	// corrupt is the number of following writes to the display memory which
+	// get a bit flipped
+	corrupt int
+}
+
+func (f *fakeI2C) Tx(addr uint16, w, r []byte) error {
+	if len(w) == 0 {
+		return nil
+	}
+
+	switch command := w[0]; {
+	case command&0xF0 == ht16k33Dimming:
+		f.dimming = command & 0x0F
+	case command == ht16k33DisplayOn:
+		f.on = true
+	case command == ht16k33DisplayOff:
+		f.on = false
+	case command < 0x10:
+		copy(f.memory[command:], w[1:])
+		if len(w) > 1 && f.corrupt > 0 {
+			f.memory[command] ^= 0x01
+			f.corrupt--
+		}
+
+		copy(r, f.memory[command:])
+	}
+
+	return nil
+}
+
+func TestHT16K33WriteVerification(t *testing.T) {
+	bus := &fakeI2C{}
+	display, err := NewSevSeg(Config{Controller: ControllerHT16K33, ControllerDigits: 4, ControllerI2C: bus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := display.SetWriteVerification(true); err != nil {
+		t.Fatal(err)
+	}
+
+	display.SetNumber(1234)
+	if err := display.WriteError(); err != nil {
+		t.Fatalf("intact write: WriteError() = %v, want nil", err)
+	}
+
+	bus.corrupt = 1
+	display.SetNumber(5678)
+	if err := display.WriteError(); err != ErrReadbackMismatch {
+		t.Fatalf("corrupted write: WriteError() = %v, want %v", err, ErrReadbackMismatch)
+	}
+
+	if err := display.WriteError(); err != nil {
+		t.Errorf("second call: WriteError() = %v, want nil", err)
+	}
+
+	// The frame was written again after the mismatch
+	h := display.backend.(*ht16k33)
+	if !bytes.Equal(bus.memory[:], h.buf[1:]) {
+		t.Errorf("display memory % x, want % x", bus.memory, h.buf[1:])
+	}
+}
+
+func TestWriteVerificationNotConfigured(t *testing.T) {
+	display, err := NewSevSeg(Config{Controller: ControllerTM1637, ControllerDigits: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := display.SetWriteVerification(true); err != ErrNotConfigured {
+		t.Errorf("TM1637: SetWriteVerification() = %v, want %v", err, ErrNotConfigured)
+	}
+}
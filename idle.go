@@ -0,0 +1,56 @@
+package sevseg
+
+// SetIdleTimeout blanks or dims the display if its content hasn't been updated
+// for the given number of ticks of the effects clock, i.e. full frames of
+// Refresh. This saves power and reduces the aging of always lit segments.
+//
+// While idle, the display is shown with idleBrightness (0 blanks the
+// display). The brightness is restored on the next content update, e.g. by
+// SetNumber or SetText. A timeout of 0 disables the idle timeout.
+func (s *SevSeg) SetIdleTimeout(ticks uint32, idleBrightness uint8) {
+	s.lock()
+	defer s.unlock()
+
+	s.idleTimeout = ticks
+	s.idleSince = s.clock
+	s.idleBrightness = min(idleBrightness, 100)
+}
+
+// currentBrightness returns the duty cycle in percent the display is
+// currently shown with, taking the idle timeout, the gamma correction and the
+// digit count compensation into account.
+func (s *SevSeg) currentBrightness() uint8 {
+	if s.onTime > 0 {
+		return 100 // SetDigitOnTime bypasses the percentage
+	}
+
+	brightness := s.brightness
+	if s.idleTimeout > 0 && s.clock-s.idleSince >= s.idleTimeout {
+		brightness = min(brightness, s.idleBrightness)
+	}
+
+	if !s.linearDuty {
+		brightness = gammaCorrect(brightness)
+	}
+
+	if s.dutyReference > 0 {
+		// A display with fewer digits lights each digit longer, so its duty
+		// cycle is reduced to match the reference display.
+		scaled := uint16(brightness) * uint16(s.slotCount()) / uint16(s.dutyReference)
+		brightness = uint8(min(scaled, 100))
+	}
+
+	return brightness
+}
+
+// gammaCorrect maps a perceived brightness in percent to a duty cycle in
+// percent with a gamma of 2, which is close to the response of the eye. Any
+// brightness above 0 keeps at least 1% duty, so it isn't blanked.
+func gammaCorrect(brightness uint8) uint8 {
+	duty := uint16(brightness) * uint16(brightness) / 100
+	if duty == 0 && brightness > 0 {
+		duty = 1
+	}
+
+	return uint8(duty)
+}
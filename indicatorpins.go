@@ -0,0 +1,72 @@
+package sevseg
+
+// hasColon reports whether the display has a colon, on the ClockIndicatorPin
+// or the ColonPin.
+func (s *SevSeg) hasColon() bool {
+	return s.colonSegments != 0 || s.colonPin != nil
+}
+
+// setColonLit turns the colon on or off without committing the content.
+func (s *SevSeg) setColonLit(on bool) {
+	if s.colonPin != nil {
+		s.colonLit = on
+		return
+	}
+
+	indicator := s.auxLEDGroups
+	if on {
+		s.auxDisplay[indicator] |= s.colonSegments
+	} else {
+		s.auxDisplay[indicator] &^= s.colonSegments
+	}
+}
+
+// setSeparatorColon lights the colon separating the fields of the content,
+// or turns it off if it has been lit that way. A colon set by SetColon is
+// kept.
+func (s *SevSeg) setSeparatorColon(on bool) {
+	if !s.hasColon() || !on && !s.separatorColon {
+		return
+	}
+
+	s.endClockFace() // Before the change, which would be overwritten otherwise
+	s.setColonLit(on)
+	s.separatorColon = on
+}
+
+// setIndicatorPin turns the LED on a dedicated ColonPin or ApostrophePin on
+// or off.
+func (s *SevSeg) setIndicatorPin(lit *bool, on bool) {
+	s.endClockFace() // Before the change, which would be overwritten otherwise
+
+	*lit = on
+	s.commit(s.contentType)
+}
+
+// lightIndicatorPins turns on the dedicated indicator pins which are lit,
+// together with the first step of a frame. clearDigitPins turns them off
+// again with the digits.
+func (s *SevSeg) lightIndicatorPins() {
+	if s.currentDigitToRefresh != 0 || !s.colonLit && !s.apostropheLit {
+		return
+	}
+
+	if s.colonLit {
+		s.setSegmentPin(s.colonPin, true)
+	}
+
+	if s.apostropheLit {
+		s.setSegmentPin(s.apostrophePin, true)
+	}
+
+	s.flushBanks()
+}
+
+// clearIndicatorPins turns off the dedicated indicator pins.
+func (s *SevSeg) clearIndicatorPins() {
+	for _, pin := range []OutputPin{s.colonPin, s.apostrophePin} {
+		if pin != nil {
+			s.setSegmentPin(pin, false)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package sevseg
+
+// keypadMaxDigits is the number of digits KeypadEcho accepts at most, any
+// value with 9 digits fits the uint32 of Value.
+const keypadMaxDigits = 9
+
+// KeypadEcho echoes digits entered on a keypad. Newly entered digits are
+// shifted in from the right, like on a cash register, and can be removed
+// again with Backspace.
+type KeypadEcho struct {
+	display *SevSeg
+	digits  []uint8 // Entered digits, the first element is the oldest
+}
+
+// NewKeypadEcho creates a new keypad echo on the display and clears it.
+func NewKeypadEcho(display *SevSeg) (*KeypadEcho, error) {
+	if display == nil {
+		return nil, ErrInvalidArgument
+	}
+
+	k := &KeypadEcho{
+		display: display,
+		digits:  make([]uint8, 0, min(len(display.digitPins), keypadMaxDigits)),
+	}
+	k.render()
+
+	return k, nil
+}
+
+// Push shifts a newly entered digit (0-9) in from the right. At most 9 digits
+// are accepted, even on wider displays, so Value can't overflow.
+//
+// Returns ErrInvalidArgument if the digit is invalid or ErrTooManyDigits if
+// the display or the 9 digits are already full.
+func (k *KeypadEcho) Push(digit uint8) error {
+	if digit > 9 {
+		return ErrInvalidArgument
+	}
+
+	if len(k.digits) >= min(len(k.display.digitPins), keypadMaxDigits) {
+		return ErrTooManyDigits
+	}
+
+	k.digits = append(k.digits, digit)
+	k.render()
+
+	return nil
+}
+
+// Backspace removes the most recently entered digit.
+//
+// Returns false if no digits have been entered.
+func (k *KeypadEcho) Backspace() bool {
+	if len(k.digits) == 0 {
+		return false
+	}
+
+	k.digits = k.digits[:len(k.digits)-1]
+	k.render()
+
+	return true
+}
+
+// Reset removes all entered digits.
+func (k *KeypadEcho) Reset() {
+	k.digits = k.digits[:0]
+	k.render()
+}
+
+// Len returns the number of entered digits, including leading zeros.
+func (k *KeypadEcho) Len() uint8 {
+	return uint8(len(k.digits))
+}
+
+// Value returns the entered digits as a number.
+func (k *KeypadEcho) Value() uint32 {
+	value := uint32(0)
+	for _, digit := range k.digits {
+		value = value*10 + uint32(digit)
+	}
+
+	return value
+}
+
+// render writes the entered digits right-aligned to the display. Unused
+// digits are blank or zero, depending on UseLeadingZeros.
+func (k *KeypadEcho) render() {
+	k.display.lock()
+	defer k.display.unlock()
+
+	initPattern := k.display.getSegmentCode(36) // BLANK
+	if k.display.format.UseLeadingZeros {
+		initPattern = k.display.getSegmentCode(0) // ZERO
+	}
+
+	for i := range k.display.updatedDisplay {
+		k.display.updatedDisplay[i] = initPattern
+	}
+
+	for i, digit := range k.digits {
+		k.display.updatedDisplay[len(k.digits)-1-i] = k.display.getSegmentCode(digit)
+	}
+
+	k.display.commitNumber(ContentNumber, false)
+}
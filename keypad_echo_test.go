@@ -0,0 +1,38 @@
+//go:build !tinygo
+
+package sevseg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestKeypadEchoValueFits(t *testing.T) {
+	s, err := NewSevSeg(Config{
+		DigitPins:   SimulatedPins(10),
+		SegmentPins: SimulatedPins(8),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	k, err := NewKeypadEcho(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range keypadMaxDigits {
+		if err := k.Push(9); err != nil {
+			t.Fatalf("digit %d: %v", i+1, err)
+		}
+	}
+
+	// A 10th digit would overflow the uint32 of Value
+	if err := k.Push(9); !errors.Is(err, ErrTooManyDigits) {
+		t.Fatalf("digit 10: err = %v, want ErrTooManyDigits", err)
+	}
+
+	if value := k.Value(); value != 999999999 {
+		t.Errorf("Value() = %d, want 999999999", value)
+	}
+}
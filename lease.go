@@ -0,0 +1,71 @@
+package sevseg
+
+import "time"
+
+// Lease grants a firmware component the right to update a shared display, see
+// Claim.
+type Lease struct {
+	display  *SevSeg
+	priority uint8
+	expires  time.Time // Zero if the lease doesn't expire
+}
+
+// Claim requests the display for a firmware component, so several independent
+// components can share it, e.g. an OTA-progress module temporarily taking
+// over from the clock module. The claim succeeds if no other lease with the
+// same or a higher priority is active; a lower-priority lease is preempted.
+// The lease expires after ttl, a ttl of 0 never expires. Returns
+// ErrDisplayClaimed if the display can't be claimed.
+//
+// Arbitration is cooperative: components should only update the display
+// while their lease is active. After a higher-priority lease ends, a
+// preempted component has to claim the display again and redraw its content.
+func (s *SevSeg) Claim(priority uint8, ttl time.Duration) (*Lease, error) {
+	s.lock()
+	defer s.unlock()
+
+	if s.lease != nil && s.lease.Active() && s.lease.priority >= priority {
+		return nil, ErrDisplayClaimed
+	}
+
+	lease := &Lease{display: s, priority: priority}
+	lease.setTTL(ttl)
+	s.lease = lease
+
+	return lease, nil
+}
+
+// Active reports whether the lease has neither expired, been released nor
+// been preempted by a higher-priority claim.
+func (l *Lease) Active() bool {
+	return l.display.lease == l && (l.expires.IsZero() || time.Now().Before(l.expires))
+}
+
+// Renew extends an active lease by ttl from now, a ttl of 0 never expires.
+//
+// Returns false if the lease isn't active anymore.
+func (l *Lease) Renew(ttl time.Duration) bool {
+	if !l.Active() {
+		return false
+	}
+
+	l.setTTL(ttl)
+
+	return true
+}
+
+// Release ends the lease, so components with a lower priority can claim the
+// display again.
+func (l *Lease) Release() {
+	if l.display.lease == l {
+		l.display.lease = nil
+	}
+}
+
+// setTTL sets the expiry of the lease to ttl from now.
+func (l *Lease) setTTL(ttl time.Duration) {
+	l.expires = time.Time{}
+	if ttl > 0 {
+		l.expires = time.Now().Add(ttl)
+	}
+}
@@ -0,0 +1,57 @@
+package sevseg
+
+import "time"
+
+// FrameRecord describes one digit of a committed frame, see LogDriver.
+type FrameRecord struct {
+	Time       time.Time // Time the frame was committed
+	Frame      uint32    // Sequence number of the frame, starting at 0
+	Digit      uint8     // Zero indexed from the right
+	Pattern    uint8     // Segment pattern in the format of SetSegment
+	Brightness uint8     // Brightness the display was shown with, 0-100
+}
+
+// logDriver is a Driver emitting every frame as FrameRecords.
+type logDriver struct {
+	display *SevSeg
+	sink    func(record FrameRecord)
+	frame   uint32
+}
+
+// LogDriver returns a Driver which emits every frame committed to display as
+// one FrameRecord per digit to sink, e.g. to write them to a serial log or an
+// SD card for off-device analysis of long-running field tests:
+//
+//	display.SetMirror(sevseg.LogDriver(display, func(r sevseg.FrameRecord) {
+//		logger.Write(r)
+//	}))
+//
+// The records of a frame share its time and sequence number. Brightness
+// changes without new content, e.g. by SetBrightness or the idle timeout,
+// don't emit records. The driver must be mirrored on display itself, see
+// SetMirror and Tee.
+func LogDriver(display *SevSeg, sink func(record FrameRecord)) Driver {
+	return &logDriver{display: display, sink: sink}
+}
+
+// WriteFrame emits the records of the frame, the right most digit first.
+func (l *logDriver) WriteFrame(frame []uint8) {
+	now := time.Now()
+
+	brightness := uint8(0)
+	if l.display.enabled && l.display.shown() {
+		brightness = l.display.currentBrightness()
+	}
+
+	for i, pattern := range frame {
+		l.sink(FrameRecord{
+			Time:       now,
+			Frame:      l.frame,
+			Digit:      uint8(i),
+			Pattern:    pattern,
+			Brightness: brightness,
+		})
+	}
+
+	l.frame++
+}
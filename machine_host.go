@@ -0,0 +1,54 @@
+//go:build !tinygo
+
+package sevseg
+
+// Stand-ins for the TinyGo machine types, so the package can be built on the
+// host to prototype text, scrolling and animations without hardware. The pins
+// do nothing, the content is observed through a Driver like Terminal.
+type (
+	machinePin       uint8
+	machinePinMode   uint8
+	machinePWMConfig struct{ Period uint64 }
+)
+
+// PinConfig is the configuration passed to OutputPin.Configure, which is
+// machine.PinConfig with TinyGo.
+type PinConfig struct {
+	Mode machinePinMode
+}
+
+const machineNoPin machinePin = 0xff
+
+const (
+	machinePinOutput machinePinMode = iota
+	machinePinInput
+	machinePinInputPullup
+)
+
+// Configure does nothing.
+func (p machinePin) Configure(config PinConfig) {}
+
+// High does nothing.
+func (p machinePin) High() {}
+
+// Low does nothing.
+func (p machinePin) Low() {}
+
+// Set does nothing.
+func (p machinePin) Set(high bool) {}
+
+// Get returns true, like an input with a pull-up and nothing connected.
+func (p machinePin) Get() bool {
+	return true
+}
+
+// SimulatedPins returns n distinct pins for the DigitPins or SegmentPins of a
+// display simulated on the host.
+func SimulatedPins(n int) []OutputPin {
+	pins := make([]OutputPin, n)
+	for i := range pins {
+		pins[i] = machinePin(i)
+	}
+
+	return pins
+}
@@ -0,0 +1,25 @@
+//go:build tinygo
+
+package sevseg
+
+import "machine"
+
+// The machine types used by the package. They are aliases, so the package
+// takes the pins and configurations of the TinyGo machine package as they
+// are. On the host, machine_host.go replaces them to simulate displays.
+type (
+	machinePin       = machine.Pin
+	machinePinMode   = machine.PinMode
+	machinePWMConfig = machine.PWMConfig
+)
+
+// PinConfig is the configuration passed to OutputPin.Configure, which is
+// machine.PinConfig.
+type PinConfig = machine.PinConfig
+
+const (
+	machineNoPin          = machine.NoPin
+	machinePinOutput      = machine.PinOutput
+	machinePinInput       = machine.PinInput
+	machinePinInputPullup = machine.PinInputPullup
+)
@@ -0,0 +1,153 @@
+package sevseg
+
+import "bytes"
+
+// Registers of the MAX7219, the digits are at 0x01-0x08
+const (
+	max7219DecodeMode  = 0x09
+	max7219Intensity   = 0x0A
+	max7219ScanLimit   = 0x0B
+	max7219Shutdown    = 0x0C
+	max7219DisplayTest = 0x0F
+)
+
+// max7219ModuleDigits is the number of digits of a MAX7219, and so of each
+// module in a chain.
+const max7219ModuleDigits = 8
+
+// SPI is an SPI bus of the board, e.g. machine.SPI0. The TinyGo machine
+// package has a different type for each board, all of which implement this
+// interface.
+type SPI interface {
+	Tx(w, r []byte) error
+}
+
+// max7219 is the backend for the MAX7219 and MAX7221 controllers. Every
+// register is written as a 16-bit word and latched with the LOAD pin. Frames
+// and brightness are only sent if they changed.
+//
+// Several modules of 8 digits can be chained, DOUT of a module connected to
+// DIN of the next one. A word per module is then shifted through the chain
+// before latching, the first word ending up in the last module. The module
+// connected to the board is the left most one.
+type max7219 struct {
+	bus       SPI
+	load      machinePin
+	modules   uint8   // Modules in the chain
+	frame     []uint8 // Last frame sent
+	intensity uint8   // Last intensity sent, 0xFF if shut down
+	sent      bool
+	buf       []byte // A word per module
+}
+
+// newMAX7219 creates a MAX7219 backend driving the given number of digits,
+// up to 8 on a single controller or a multiple of 8 on a chain of modules.
+func newMAX7219(bus SPI, load machinePin, digits uint8) *max7219 {
+	modules := max(digits/max7219ModuleDigits, 1)
+	m := &max7219{
+		bus:       bus,
+		load:      load,
+		modules:   modules,
+		frame:     make([]uint8, digits),
+		intensity: 0xFF,
+		buf:       make([]byte, 2*int(modules)),
+	}
+
+	m.load.Configure(PinConfig{Mode: machinePinOutput})
+	m.load.High()
+
+	// The registers are undefined after power-up
+	m.writeRegister(max7219DisplayTest, 0)
+	m.writeRegister(max7219DecodeMode, 0) // Raw segment patterns
+	m.writeRegister(max7219ScanLimit, min(digits, max7219ModuleDigits)-1)
+	m.writeRegister(max7219Shutdown, 0)
+
+	return m
+}
+
+// writeFrame writes the frame to the digit registers, the first element to
+// DIG0 of the right most module.
+func (m *max7219) writeFrame(frame []uint8) {
+	if m.sent && bytes.Equal(frame, m.frame) {
+		return
+	}
+
+	copy(m.frame, frame)
+	m.sent = true
+
+	// The right most module is the last one in the chain, its word is sent
+	// first
+	for digit := range min(len(frame), max7219ModuleDigits) {
+		for word := range int(m.modules) {
+			m.buf[2*word] = uint8(digit + 1)
+			m.buf[2*word+1] = max7219Pattern(frame[word*max7219ModuleDigits+digit])
+		}
+
+		m.send()
+	}
+}
+
+// moduleCount returns the number of modules in the chain.
+func (m *max7219) moduleCount() uint8 {
+	return m.modules
+}
+
+// setBrightness maps the brightness to the 16 levels of the intensity
+// register, 0 shuts the controller down.
+func (m *max7219) setBrightness(brightness uint8) {
+	intensity := uint8(0xFF)
+	if brightness > 0 {
+		intensity = uint8((uint16(brightness) - 1) * 16 / 100)
+	}
+
+	if intensity == m.intensity {
+		return
+	}
+
+	if intensity == 0xFF {
+		m.writeRegister(max7219Shutdown, 0)
+	} else {
+		m.writeRegister(max7219Intensity, intensity)
+		if m.intensity == 0xFF {
+			m.writeRegister(max7219Shutdown, 1)
+		}
+	}
+
+	m.intensity = intensity
+}
+
+// writeRegister writes a value to a register of all modules.
+func (m *max7219) writeRegister(register, value uint8) {
+	for word := range int(m.modules) {
+		m.buf[2*word] = register
+		m.buf[2*word+1] = value
+	}
+
+	m.send()
+}
+
+// send shifts the words in the buffer through the chain and latches them.
+func (m *max7219) send() {
+	m.load.Low()
+	m.bus.Tx(m.buf, nil)
+	m.load.High()
+}
+
+// max7219Pattern converts a segment pattern to the bit order of the
+// controller: DP is D7, followed by A (D6) to G (D0).
+func max7219Pattern(pattern uint8) uint8 {
+	result := pattern & 0b10000000 // DP
+	for i := range 7 {
+		if pattern&(1<<i) != 0 {
+			result |= 1 << (6 - i)
+		}
+	}
+
+	return result
+}
+
+// validMAX7219Digits reports whether the number of digits can be driven by a
+// single MAX7219 or a chain of up to 8 modules.
+func validMAX7219Digits(digits uint8) bool {
+	return digits <= max7219ModuleDigits || digits%max7219ModuleDigits == 0 && digits <= 8*max7219ModuleDigits
+}
@@ -0,0 +1,97 @@
+package sevseg
+
+import "testing"
+
+// fakeSPI is a chain of MAX7219 modules, recording the registers written to
+// each module. Module 0 is the one connected to the bus.
+type fakeSPI struct {
+	modules []map[uint8]uint8
+}
+
+func newFakeSPI() *fakeSPI {
+	return &fakeSPI{}
+}
+
+// Tx latches a word per module, the first word ending up in the last module.
+func (f *fakeSPI) Tx(w, r []byte) error {
+	words := len(w) / 2
+	for len(f.modules) < words {
+		f.modules = append(f.modules, make(map[uint8]uint8))
+	}
+
+	for i := range words {
+		f.modules[words-1-i][w[2*i]] = w[2*i+1]
+	}
+
+	return nil
+}
+
+// registers returns the registers of the module.
+func (f *fakeSPI) registers(module int) map[uint8]uint8 {
+	return f.modules[module]
+}
+
+// moduleText returns the patterns of the digits of a module from left to
+// right, in the bit order of this package.
+func moduleText(bus *fakeSPI, module int) []uint8 {
+	patterns := make([]uint8, 8)
+	for digit := range 8 {
+		raw := bus.registers(module)[uint8(digit+1)]
+
+		// Reverse max7219Pattern, DP is D7 followed by A (D6) to G (D0)
+		pattern := raw & 0b10000000
+		for i := range 7 {
+			if raw&(1<<(6-i)) != 0 {
+				pattern |= 1 << i
+			}
+		}
+
+		patterns[7-digit] = pattern
+	}
+
+	return patterns
+}
+
+func TestMAX7219SetTextOnModule(t *testing.T) {
+	bus := newFakeSPI()
+	display, err := NewSevSeg(Config{Controller: ControllerMAX7219, ControllerDigits: 24, ControllerSPI: bus})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(bus.modules) != 3 || bus.registers(2)[max7219ScanLimit] != 7 {
+		t.Fatalf("chain of %d modules, scan limit %d, want 3 modules and 7", len(bus.modules), bus.registers(2)[max7219ScanLimit])
+	}
+
+	if err := display.SetTextOnModule(0, "AB"); err != nil {
+		t.Fatal(err)
+	}
+	if err := display.SetTextOnModule(2, "C"); err != nil {
+		t.Fatal(err)
+	}
+
+	blank := display.getSegmentCode(36)
+	want := [][]uint8{
+		{display.getSegmentCode(10), display.getSegmentCode(11), blank, blank, blank, blank, blank, blank},
+		{blank, blank, blank, blank, blank, blank, blank, blank},
+		{display.getSegmentCode(12), blank, blank, blank, blank, blank, blank, blank},
+	}
+
+	for module := range want {
+		if got := moduleText(bus, module); string(got) != string(want[module]) {
+			t.Errorf("module %d: patterns %07b, want %07b", module, got, want[module])
+		}
+	}
+
+	if err := display.SetTextOnModule(3, "D"); err != ErrOutOfRange {
+		t.Errorf("module 3: err = %v, want %v", err, ErrOutOfRange)
+	}
+
+	if err := display.SetTextOnModule(1, "123456789"); err != ErrTooManyDigits {
+		t.Errorf("9 characters: err = %v, want %v", err, ErrTooManyDigits)
+	}
+
+	if _, err := NewSevSeg(Config{Controller: ControllerMAX7219, ControllerDigits: 12, ControllerSPI: bus}); err != ErrInvalidConfig {
+		t.Errorf("12 digits: err = %v, want %v", err, ErrInvalidConfig)
+	}
+}
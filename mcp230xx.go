@@ -0,0 +1,149 @@
+package sevseg
+
+// Registers of the MCP230xx in the default bank mode (IOCON.BANK = 0), in
+// which the registers of port A and B of the MCP23017 are adjacent.
+const (
+	mcp230xxDefaultAddress = 0x20
+	mcp230xxIODIR          = 0x00 // Direction, 1 is input
+	mcp23008GPPU           = 0x06 // Pull-ups, 1 is enabled
+	mcp23008OLAT           = 0x0A // Output latch
+	mcp23017GPPUA          = 0x0C // Pull-ups of port A, followed by port B
+	mcp23017OLATA          = 0x14 // Output latch of port A, followed by port B
+)
+
+// MCP230xx is an MCP23017 (16 pins on the ports A and B) or MCP23008 (8
+// pins) I2C GPIO expander, whose pins can be used as DigitPins, SegmentPins
+// and AuxDigitPins. The pins are written port-wide in batches, so setting all
+// segments of a digit takes a single I2C transaction instead of one per pin.
+// With an MCP23017, a whole 8-digit display can be driven with the two I2C
+// pins of the MCU.
+type MCP230xx struct {
+	bus     I2C
+	address uint16
+	ports   uint8
+	olat    uint8 // Register of the output latch of the first port
+	gppu    uint8 // Register of the pull-ups of the first port
+
+	latch        [2]uint8 // Levels set by the pins
+	latchWritten [2]uint8
+	dir          [2]uint8 // Directions set by Configure, 1 is input
+	dirWritten   [2]uint8
+	pullup       [2]uint8 // Pull-ups set by Configure, 1 is enabled
+	pullupSent   [2]uint8
+	sent         bool
+	buf          [3]byte
+}
+
+// NewMCP23017 creates an MCP23017 on the bus, which must be configured
+// before. An address of 0 selects the default address 0x20.
+func NewMCP23017(bus I2C, address uint16) *MCP230xx {
+	return newMCP230xx(bus, address, 2, mcp23017OLATA, mcp23017GPPUA)
+}
+
+// NewMCP23008 creates an MCP23008 on the bus, which must be configured
+// before. An address of 0 selects the default address 0x20.
+func NewMCP23008(bus I2C, address uint16) *MCP230xx {
+	return newMCP230xx(bus, address, 1, mcp23008OLAT, mcp23008GPPU)
+}
+
+// newMCP230xx creates an expander with the given number of ports.
+func newMCP230xx(bus I2C, address uint16, ports, olat, gppu uint8) *MCP230xx {
+	if address == 0 {
+		address = mcp230xxDefaultAddress
+	}
+
+	// All pins are inputs without pull-ups after power-on
+	return &MCP230xx{
+		bus:        bus,
+		address:    address,
+		ports:      ports,
+		olat:       olat,
+		gppu:       gppu,
+		dir:        [2]uint8{0xFF, 0xFF},
+		dirWritten: [2]uint8{0xFF, 0xFF},
+	}
+}
+
+// Pin returns a pin of the expander, or nil if n is out of range. The pins
+// GPA0-GPA7 of an MCP23017 are 0-7, GPB0-GPB7 are 8-15. The pins of an
+// MCP23008 are GP0-GP7.
+func (m *MCP230xx) Pin(n uint8) OutputPin {
+	if n >= 8*m.ports {
+		return nil
+	}
+
+	return mcp230xxPin{expander: m, port: n / 8, mask: 1 << (n % 8)}
+}
+
+// Flush writes the levels, pull-ups and directions of all pins to the
+// expander if they changed, the levels first so an output doesn't glitch when
+// it's enabled.
+func (m *MCP230xx) Flush() {
+	if !m.sent || m.latch != m.latchWritten {
+		if m.write(m.olat, m.latch) {
+			m.latchWritten = m.latch
+			m.sent = true
+		}
+	}
+
+	if m.pullup != m.pullupSent && m.write(m.gppu, m.pullup) {
+		m.pullupSent = m.pullup
+	}
+
+	if m.dir != m.dirWritten && m.write(mcp230xxIODIR, m.dir) {
+		m.dirWritten = m.dir
+	}
+}
+
+// write writes the values of all ports to the registers starting at reg.
+// Returns false if the transaction failed.
+func (m *MCP230xx) write(reg uint8, values [2]uint8) bool {
+	m.buf[0] = reg
+	copy(m.buf[1:], values[:m.ports])
+
+	return m.bus.Tx(m.address, m.buf[:1+m.ports], nil) == nil
+}
+
+// mcp230xxPin is a pin of an MCP230xx, its level is written by Flush.
+type mcp230xxPin struct {
+	expander *MCP230xx
+	port     uint8
+	mask     uint8
+}
+
+// Configure makes the pin an output, or an input for any input mode, e.g.
+// while SetSharedPinWindow releases the segment pins, with the next Flush.
+// The pull-up is enabled for machine.PinInputPullup.
+func (p mcp230xxPin) Configure(config PinConfig) {
+	e := p.expander
+
+	if config.Mode == machinePinOutput {
+		e.dir[p.port] &^= p.mask
+		e.pullup[p.port] &^= p.mask
+
+		return
+	}
+
+	e.dir[p.port] |= p.mask
+
+	if config.Mode == machinePinInputPullup {
+		e.pullup[p.port] |= p.mask
+	} else {
+		e.pullup[p.port] &^= p.mask
+	}
+}
+
+// High sets the pin high with the next Flush.
+func (p mcp230xxPin) High() {
+	p.expander.latch[p.port] |= p.mask
+}
+
+// Low sets the pin low with the next Flush.
+func (p mcp230xxPin) Low() {
+	p.expander.latch[p.port] &^= p.mask
+}
+
+// Bank returns the expander of the pin.
+func (p mcp230xxPin) Bank() PinBank {
+	return p.expander
+}
@@ -0,0 +1,99 @@
+package sevseg
+
+import "testing"
+
+// fakeMCP23017 is an MCP23017 on an I2C bus, keeping the registers written
+// with sequential addressing.
+type fakeMCP23017 struct {
+	registers [0x16]byte
+}
+
+func newFakeMCP23017() *fakeMCP23017 {
+	f := &fakeMCP23017{}
+	f.registers[mcp230xxIODIR] = 0xFF
+	f.registers[mcp230xxIODIR+1] = 0xFF
+
+	return f
+}
+
+func (f *fakeMCP23017) Tx(addr uint16, w, r []byte) error {
+	if len(w) > 0 {
+		copy(f.registers[w[0]:], w[1:])
+	}
+
+	return nil
+}
+
+func TestMCP230xxPinModes(t *testing.T) {
+	bus := newFakeMCP23017()
+	mcp := NewMCP23017(bus, 0)
+
+	tests := []struct {
+		mode         machinePinMode
+		input, pulls bool
+	}{
+		{machinePinOutput, false, false},
+		{machinePinInputPullup, true, true},
+		{machinePinInput, true, false},
+		{machinePinOutput, false, false},
+	}
+
+	pin := mcp.Pin(9) // GPB1
+	for _, tt := range tests {
+		pin.Configure(PinConfig{Mode: tt.mode})
+		mcp.Flush()
+
+		if input := bus.registers[mcp230xxIODIR+1]&0x02 != 0; input != tt.input {
+			t.Errorf("mode %d: input = %t, want %t", tt.mode, input, tt.input)
+		}
+
+		if pulls := bus.registers[mcp23017GPPUA+1]&0x02 != 0; pulls != tt.pulls {
+			t.Errorf("mode %d: pull-up = %t, want %t", tt.mode, pulls, tt.pulls)
+		}
+	}
+}
+
+func TestMCP230xxSharedPinWindow(t *testing.T) {
+	bus := newFakeMCP23017()
+	mcp := NewMCP23017(bus, 0)
+
+	cfg := Config{}
+	for n := range uint8(8) {
+		cfg.SegmentPins = append(cfg.SegmentPins, mcp.Pin(n))
+	}
+	for n := range uint8(4) {
+		cfg.DigitPins = append(cfg.DigitPins, mcp.Pin(8+n))
+	}
+
+	s, err := NewSevSeg(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	windows := 0
+	err = s.SetSharedPinWindow(func() {
+		windows++
+		if dir := bus.registers[mcp230xxIODIR]; dir != 0xFF {
+			t.Errorf("window: segment pin directions %08b, want all inputs", dir)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 * len(cfg.DigitPins) {
+		s.Refresh()
+	}
+
+	if windows == 0 {
+		t.Fatal("window not opened")
+	}
+
+	if dir := bus.registers[mcp230xxIODIR]; dir != 0x00 {
+		t.Errorf("after the window: segment pin directions %08b, want all outputs", dir)
+	}
+
+	if dir := bus.registers[mcp230xxIODIR+1] & 0x0F; dir != 0x00 {
+		t.Errorf("digit pin directions %04b, want all outputs", dir)
+	}
+}
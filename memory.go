@@ -0,0 +1,113 @@
+package sevseg
+
+import "unsafe"
+
+// pwmChannelEntrySize approximates the RAM a HardwarePWM digit pin takes in
+// the channel map: the key, the value and the bookkeeping of the map.
+const pwmChannelEntrySize = unsafe.Sizeof(OutputPin(nil)) + unsafe.Sizeof(pwmChannelMap{}) + 2*unsafe.Sizeof(uintptr(0))
+
+// MemoryFootprint returns the number of bytes of RAM a display created with
+// the given configuration uses, which helps budgeting RAM on small targets
+// like the ATtiny or ATmega.
+//
+// This covers everything NewSevSeg allocates and keeps: the display itself,
+// its frame buffers (back buffer, staged and live frame), the history, the
+// tri-color state, copies of the pin slices, the PinBanks of I/O expander
+// pins, the HardwarePWM channel map and the state of a controller or the PIO
+// refresh engine. The size of the channel map is an estimate, as it depends
+// on the map implementation of the runtime, and the allocator may round each
+// allocation up. The pin slices of the configuration, its pins and the fonts
+// are referenced, not copied.
+// Some features allocate additional memory when used:
+//
+//   - SetText: len(text) bytes, plus one byte per digit if the text is longer
+//     than the display
+//   - AlternateWith, ShowWarning, Snapshot, MarkSegmentDead, SetAnnotation:
+//     one byte per digit
+//   - SetCrossfade: 9 bytes per digit, for the previous content and the
+//     levels of a SegmentLevelDriver
+//   - ScrollQueue, KeypadEcho: the queued or entered characters
+//   - RegisterFont, RegisterProfile: the entry of the name
+//
+// SetTextPatterns and PlayFrameString read string constants in place and
+// allocate nothing.
+func MemoryFootprint(cfg Config) uintptr {
+	pinSize := unsafe.Sizeof(OutputPin(nil))
+
+	digits := uintptr(len(cfg.DigitPins))
+	if cfg.Controller != NoController {
+		digits = uintptr(cfg.ControllerDigits)
+	}
+
+	size := unsafe.Sizeof(SevSeg{})
+	size += 2 * digits                         // updatedDisplay, stagedDisplay
+	size += uintptr(cfg.HistoryDepth) * digits // One frame per history entry
+	size += uintptr(len(cfg.AuxDigitPins))     // auxDisplay
+	if cfg.Controller == NoController && cfg.RefreshEngine != RefreshPIO {
+		size += digits // liveDisplay, controllers show the back buffer
+	}
+
+	if cfg.ReverseDigitOrder {
+		size += digits * pinSize
+	}
+
+	// The color of each tri-color digit
+	if len(cfg.GreenSegmentPins) > 0 || len(cfg.BlueSegmentPins) > 0 {
+		size += digits * unsafe.Sizeof(color(0))
+	}
+
+	switch {
+	case cfg.Controller != NoController:
+		return size + controllerFootprint(cfg, digits)
+	case cfg.RefreshEngine == RefreshPIO:
+		return size + digits + pioEngineSize(len(cfg.DigitPins), len(cfg.SegmentPins)) // backendFrame
+	}
+
+	// The clock indicator and the sign element are appended to a copy of the
+	// AuxDigitPins, each taking one pattern byte
+	extraPins := 0
+	if cfg.ColonSegments|cfg.ApostropheSegments != 0 {
+		extraPins++
+		size += uintptr(len(cfg.AuxDigitPins)+extraPins)*pinSize + 1
+	}
+
+	if cfg.SignMinusSegments != 0 {
+		extraPins++
+		size += uintptr(len(cfg.AuxDigitPins)+extraPins)*pinSize + 1
+	}
+
+	// The PinBanks of the pins on I/O expanders, collected like NewSevSeg
+	var banks []PinBank
+	for _, pins := range [][]OutputPin{cfg.DigitPins, cfg.AuxDigitPins, cfg.SegmentPins, cfg.GreenSegmentPins,
+		cfg.BlueSegmentPins, {cfg.ColonPin, cfg.ApostrophePin}} {
+		banks = appendBanks(banks, pins)
+	}
+	size += uintptr(cap(banks)) * unsafe.Sizeof(PinBank(nil))
+
+	if cfg.PWMType == HardwarePWM {
+		size += uintptr(len(cfg.DigitPins)+extraPins) * pwmChannelEntrySize
+	}
+
+	return size
+}
+
+// controllerFootprint returns the RAM a controller takes in addition to the
+// display: the placeholder digit pins, the frame sent to the controller and
+// the state of the backend.
+func controllerFootprint(cfg Config, digits uintptr) uintptr {
+	size := digits*unsafe.Sizeof(OutputPin(nil)) + digits
+
+	switch cfg.Controller {
+	case ControllerTM1637:
+		size += unsafe.Sizeof(tm1637{}) + digits
+	case ControllerMAX7219:
+		modules := (digits + max7219ModuleDigits - 1) / max7219ModuleDigits
+		size += unsafe.Sizeof(max7219{}) + digits + 2*modules
+	case ControllerHT16K33:
+		size += unsafe.Sizeof(ht16k33{}) + digits
+	case ControllerTM1638:
+		size += unsafe.Sizeof(tm1638{}) + 1 // One aux LED group
+	}
+
+	return size
+}
@@ -0,0 +1,80 @@
+//go:build !tinygo
+
+package sevseg
+
+import (
+	"runtime"
+	"testing"
+)
+
+// discardSPI is an SPI bus without devices, which unlike fakeSPI allocates
+// nothing.
+type discardSPI struct{}
+
+func (discardSPI) Tx(w, r []byte) error {
+	return nil
+}
+
+// allocated returns the bytes and the number of heap allocations of f, the
+// least of several runs so allocations of the runtime don't count.
+func allocated(f func()) (uint64, uint64) {
+	var before, after runtime.MemStats
+
+	bytes, mallocs := ^uint64(0), ^uint64(0)
+	for range 3 {
+		runtime.ReadMemStats(&before)
+		f()
+		runtime.ReadMemStats(&after)
+
+		bytes = min(bytes, after.TotalAlloc-before.TotalAlloc)
+		mallocs = min(mallocs, after.Mallocs-before.Mallocs)
+	}
+
+	return bytes, mallocs
+}
+
+func TestMemoryFootprint(t *testing.T) {
+	mcp := NewMCP23017(newFakeMCP23017(), 0)
+	expanderPins := make([]OutputPin, 12)
+	for i := range expanderPins {
+		expanderPins[i] = mcp.Pin(uint8(i))
+	}
+
+	tests := map[string]Config{
+		"Plain": {DigitPins: SimulatedPins(4), SegmentPins: SimulatedPins(8)},
+		"Reversed with history": {
+			DigitPins: SimulatedPins(8), SegmentPins: SimulatedPins(8),
+			ReverseDigitOrder: true, HistoryDepth: 16,
+		},
+		"Indicators": {
+			DigitPins: SimulatedPins(4), SegmentPins: SimulatedPins(8), AuxDigitPins: SimulatedPins(2),
+			ClockIndicatorPin: machinePin(20), ColonSegments: 0b11,
+			SignDigitPin: machinePin(21), SignMinusSegments: 0b1000000,
+		},
+		"Tri-color": {
+			DigitPins: SimulatedPins(4), SegmentPins: SimulatedPins(8),
+			GreenSegmentPins: SimulatedPins(8), BlueSegmentPins: SimulatedPins(8),
+		},
+		"Expander": {DigitPins: expanderPins[8:], SegmentPins: expanderPins[:8]},
+		"TM1637":   {Controller: ControllerTM1637, ControllerDigits: 6},
+		"MAX7219":  {Controller: ControllerMAX7219, ControllerDigits: 32, ControllerSPI: discardSPI{}},
+		"HT16K33":  {Controller: ControllerHT16K33, ControllerDigits: 4, ControllerI2C: &fakeI2C{}},
+	}
+
+	for name, cfg := range tests {
+		var err error
+		bytes, mallocs := allocated(func() {
+			_, err = NewSevSeg(cfg)
+		})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		// The allocator rounds each allocation up to its size class
+		footprint := uint64(MemoryFootprint(cfg))
+		if footprint > bytes || bytes > footprint+footprint/8+16*mallocs {
+			t.Errorf("%s: footprint %d bytes, NewSevSeg allocated %d bytes in %d allocations",
+				name, footprint, bytes, mallocs)
+		}
+	}
+}
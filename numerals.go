@@ -0,0 +1,48 @@
+package sevseg
+
+// Numerals are the segment patterns of the digits 0 to 9, indexed by digit.
+type Numerals [10]uint8
+
+// WesternNumerals are the default digits 0 to 9.
+var WesternNumerals = Numerals{
+	0b00111111, // 0
+	0b00000110, // 1
+	0b01011011, // 2
+	0b01001111, // 3
+	0b01100110, // 4
+	0b01101101, // 5
+	0b01111101, // 6
+	0b00000111, // 7
+	0b01111111, // 8
+	0b01101111, // 9
+}
+
+// EasternArabicNumerals approximate the Eastern Arabic digits ٠ to ٩ as far
+// as seven segments allow. Most of them resemble Latin letters, so they are
+// meant for products consistently using them, not for mixing with text.
+var EasternArabicNumerals = Numerals{
+	0b01011100, // ٠ small o, the dot is too small to be read
+	0b00000110, // ١ vertical stroke
+	0b00110011, // ٢ stroke with a hook at the top
+	0b01110011, // ٣ stroke with two hooks at the top
+	0b01111001, // ٤ mirrored 3
+	0b00111111, // ٥ circle
+	0b00000111, // ٦ stroke with a bar at the top
+	0b00111110, // ٧ V
+	0b00110111, // ٨ inverted V
+	0b01100111, // ٩ loop with a stroke
+}
+
+// SetNumerals selects the glyphs of the digits 0 to 9 for all following
+// numbers and texts, e.g. EasternArabicNumerals for a regional variant of a
+// product sharing the same hardware. The numerals are referenced, not
+// copied. Passing nil restores the digits of the font, see Config.FontName.
+//
+// The hex digits A to F and aliases set with AliasChar or AliasGlyph are not
+// affected.
+func (s *SevSeg) SetNumerals(numerals *Numerals) {
+	s.lock()
+	defer s.unlock()
+
+	s.format.Numerals = numerals
+}
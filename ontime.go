@@ -0,0 +1,47 @@
+package sevseg
+
+import "time"
+
+// SetDigitOnTime sets the brightness as the absolute time in microseconds each
+// digit is lit per Refresh call, instead of a percentage. Refresh then turns
+// the digit off again before returning, so the duty cycle is micros divided
+// by the interval between Refresh calls. This gives direct control over the
+// average current and the flicker, e.g. when tuning for a camera.
+//
+// While set, the brightness percentage, the idle dimming, the duty reference
+// and the segment compensation are bypassed and the PWM stays fully on. The
+// wait is a busy loop, which is precise for short times and also works from a
+// RefreshTimer interrupt, but blocks the caller. A value of 0 returns to the
+// percentage brightness.
+//
+// Returns ErrNotConfigured for displays with a Controller, which multiplexes
+// the digits itself.
+func (s *SevSeg) SetDigitOnTime(micros uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if s.backend != nil {
+		return ErrNotConfigured
+	}
+
+	s.onTime = micros
+	s.invalidate()
+
+	return nil
+}
+
+// endOnTime turns the lit digit off after the on time set by SetDigitOnTime.
+func (s *SevSeg) endOnTime() {
+	busyWait(s.onTime)
+
+	s.clearDigitPins()
+	s.flushBanks()
+}
+
+// busyWait waits for the given number of microseconds in a busy loop, which
+// unlike time.Sleep also works in an interrupt.
+func busyWait(micros uint16) {
+	start := time.Now()
+	for time.Since(start) < time.Duration(micros)*time.Microsecond {
+	}
+}
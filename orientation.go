@@ -0,0 +1,46 @@
+package sevseg
+
+// Orientation defines how the display is mounted or held.
+type Orientation uint8
+
+// Upright and UpsideDown define the orientation of the display. UpsideDown
+// rotates the content by 180°.
+const (
+	Upright Orientation = iota
+	UpsideDown
+)
+
+// SetOrientationProvider sets a function which reports the current orientation,
+// e.g. based on an accelerometer reading. It's polled on every tick of the
+// effects clock, i.e. once per frame of Refresh, so the content is never
+// rotated in the middle of a frame. A nil provider shows the content upright
+// again.
+//
+// When rotated, the digit order is reversed and every digit is drawn with the
+// rotated font. The decimal point can't be rotated and stays at the bottom
+// right of each digit.
+func (s *SevSeg) SetOrientationProvider(provider func() Orientation) {
+	s.lock()
+	defer s.unlock()
+
+	s.orientationProvider = provider
+	s.upsideDown = false
+}
+
+// advanceOrientation polls the orientation provider on a tick of the effects
+// clock.
+func (s *SevSeg) advanceOrientation() {
+	if s.orientationProvider == nil {
+		return
+	}
+
+	s.upsideDown = s.orientationProvider() == UpsideDown
+}
+
+// rotateSegments returns the pattern of a digit rotated by 180°, i.e. with the
+// segments A and D, B and E, and C and F swapped.
+func rotateSegments(pattern uint8) uint8 {
+	return pattern&0b11000000 | // G and DP stay
+		(pattern&0b00000111)<<3 | // A, B, C -> D, E, F
+		(pattern&0b00111000)>>3 // D, E, F -> A, B, C
+}
@@ -0,0 +1,97 @@
+package sevseg
+
+type glyph uint8
+
+// Glyph defines the separator between the halves of SetPair. Blank and Dash
+// take a digit of their own, Colon uses the colon of a 4-digit clock module
+// if ColonSegments is configured and the decimal point of the last digit of
+// the left half otherwise. None puts the halves right next to each other.
+var Glyph = struct {
+	None  glyph
+	Blank glyph
+	Dash  glyph
+	Colon glyph
+}{
+	None:  0,
+	Blank: 1,
+	Dash:  2,
+	Colon: 3,
+}
+
+type pairPadding uint8
+
+// PairPadNone, PairPadLeft, PairPadRight and PairPadBoth define which halves
+// of SetPair are padded with zeros instead of blanks.
+const (
+	PairPadNone pairPadding = iota
+	PairPadLeft
+	PairPadRight
+	PairPadBoth
+)
+
+// SetPair displays two numbers side by side, e.g. the scores of a scoreboard
+// or hours and minutes. The digits are split evenly between the halves, the
+// left half getting the extra digit if the count is odd. Each number is
+// right-aligned in its half and padded according to PairZeroPadding. A minus
+// always takes a digit of the half, the sign element isn't used.
+//
+// Returns ErrTooManyDigits if a number doesn't fit its half and
+// ErrNoDecimalPointPin if the colon needs the decimal point but the display
+// lacks the decimal point pin. The display is left untouched on failure.
+func (s *SevSeg) SetPair(left, right int16, separator glyph) error {
+	s.lock()
+	defer s.unlock()
+
+	width := len(s.digitPins)
+	if separator == Glyph.Blank || separator == Glyph.Dash {
+		width--
+	}
+
+	useDP := separator == Glyph.Colon && !s.hasColon()
+	if useDP && s.segmentCount() < 8 {
+		return ErrNoDecimalPointPin
+	}
+
+	rightWidth := width / 2
+	leftStart := len(s.digitPins) - (width - rightWidth)
+	frame := make([]uint8, len(s.digitPins))
+
+	leftZeros := s.pairPadding == PairPadLeft || s.pairPadding == PairPadBoth
+	if err := s.renderHalf(frame[leftStart:], left, leftZeros); err != nil {
+		return err
+	}
+
+	rightZeros := s.pairPadding == PairPadRight || s.pairPadding == PairPadBoth
+	if err := s.renderHalf(frame[:rightWidth], right, rightZeros); err != nil {
+		return err
+	}
+
+	switch {
+	case separator == Glyph.Dash:
+		frame[rightWidth] = s.getSegmentCode(37) // MINUS
+	case separator == Glyph.Blank:
+		frame[rightWidth] = s.getSegmentCode(36) // BLANK
+	case useDP:
+		frame[leftStart] |= s.getSegmentCode(38) // DECIMAL POINT
+	}
+
+	copy(s.updatedDisplay, frame)
+
+	if separator == Glyph.Colon {
+		s.commitSeparated()
+	} else {
+		s.commitNumber(ContentNumber, false)
+	}
+
+	return nil
+}
+
+// renderHalf writes a signed number of SetPair right-aligned into dst.
+func (s *SevSeg) renderHalf(dst []uint8, number int16, zeros bool) error {
+	magnitude := uint32(int32(number))
+	if number < 0 {
+		magnitude = uint32(-int32(number))
+	}
+
+	return s.format.padded(dst, magnitude, number < 0, 10, 1, zeros)
+}
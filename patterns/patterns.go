@@ -0,0 +1,104 @@
+// Package patterns provides test patterns for 7-segment displays, e.g. for
+// burn-in tests or to check the wiring with custom timing. Each frame is in
+// the format of sevseg.SetSegment and sized to the display, and sequences of
+// frames can be played with sevseg.PlayFrames:
+//
+//	display.SetSegment(patterns.AllOn(display))
+//	display.PlayFrames(patterns.SegmentWalk(display), 50)
+package patterns
+
+const (
+	segmentsNoDP = 0b01111111
+	allSegments  = 0b11111111
+)
+
+// Display is the part of a display the patterns are sized to, implemented by
+// *sevseg.SevSeg.
+type Display interface {
+	GetDisplayWidth() uint8
+	HasDecimalPoint() bool
+}
+
+// AllOn returns a frame lighting every segment of every digit, including the
+// decimal points if the display has them.
+func AllOn(d Display) []uint8 {
+	frame := make([]uint8, d.GetDisplayWidth())
+	for i := range frame {
+		frame[i] = segmentMask(d)
+	}
+
+	return frame
+}
+
+// Outline returns a frame lighting the outer edge of the display, i.e.
+// segments A and D of every digit, B and C of the right most digit and E and
+// F of the left most digit.
+func Outline(d Display) []uint8 {
+	frame := make([]uint8, d.GetDisplayWidth())
+	for i := range frame {
+		frame[i] = 0b00001001 // A, D
+	}
+
+	if len(frame) > 0 {
+		frame[0] |= 0b00000110            // B, C
+		frame[len(frame)-1] |= 0b00110000 // E, F
+	}
+
+	return frame
+}
+
+// Checkerboard returns two frames lighting complementary halves of the
+// segments, alternating between adjacent digits. Played in a loop, every
+// segment is lit half of the time and each segment is next to unlit ones,
+// which shows shorts between segment lines.
+func Checkerboard(d Display) [][]uint8 {
+	even := uint8(0b01010101) & segmentMask(d) // A, C, E, G
+	odd := uint8(0b10101010) & segmentMask(d)  // B, D, F, DP
+
+	frames := [][]uint8{
+		make([]uint8, d.GetDisplayWidth()),
+		make([]uint8, d.GetDisplayWidth()),
+	}
+
+	for i := range frames[0] {
+		if i%2 == 0 {
+			frames[0][i], frames[1][i] = even, odd
+		} else {
+			frames[0][i], frames[1][i] = odd, even
+		}
+	}
+
+	return frames
+}
+
+// SegmentWalk returns one frame per segment of every digit, each lighting
+// only that segment: A-G and DP of the right most digit, then of the next
+// digit to the left and so on. This shows missing or swapped segment and
+// digit lines.
+func SegmentWalk(d Display) [][]uint8 {
+	width := int(d.GetDisplayWidth())
+	segments := 7
+	if d.HasDecimalPoint() {
+		segments = 8
+	}
+
+	frames := make([][]uint8, 0, width*segments)
+	for digit := range width {
+		for segment := range segments {
+			frame := make([]uint8, width)
+			frame[digit] = 1 << segment
+			frames = append(frames, frame)
+		}
+	}
+
+	return frames
+}
+
+// segmentMask returns the segments of a digit of the display.
+func segmentMask(d Display) uint8 {
+	if d.HasDecimalPoint() {
+		return allSegments
+	}
+
+	return segmentsNoDP
+}
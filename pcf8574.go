@@ -0,0 +1,78 @@
+package sevseg
+
+const pcf8574DefaultAddress = 0x20
+
+// PCF8574 is a PCF8574 8-bit I2C GPIO expander, whose pins can be used as
+// DigitPins, SegmentPins and AuxDigitPins. A whole display can thus be driven
+// over I2C with two MCU pins, e.g. with one expander for the segments and one
+// for the digits. The pins are written in batches, so each step of Refresh
+// takes at most one I2C transaction per expander.
+//
+// The outputs sink up to 25 mA, but only source about 100 µA through their
+// weak pull-up, so active-high lines need a transistor driver.
+type PCF8574 struct {
+	bus     I2C
+	address uint16
+	state   uint8 // Levels set by the pins
+	written uint8 // Levels last written to the expander
+	sent    bool
+	buf     [1]byte
+}
+
+// NewPCF8574 creates a PCF8574 on the bus, which must be configured before.
+// An address of 0 selects the default address 0x20, a PCF8574A usually
+// responds at 0x38.
+func NewPCF8574(bus I2C, address uint16) *PCF8574 {
+	if address == 0 {
+		address = pcf8574DefaultAddress
+	}
+
+	// All pins are high after power-on
+	return &PCF8574{bus: bus, address: address, state: 0xFF}
+}
+
+// Pin returns the pin P0-P7 of the expander, or nil if n is out of range.
+func (p *PCF8574) Pin(n uint8) OutputPin {
+	if n > 7 {
+		return nil
+	}
+
+	return pcf8574Pin{expander: p, mask: 1 << n}
+}
+
+// Flush writes the levels of all pins to the expander if they changed.
+func (p *PCF8574) Flush() {
+	if p.sent && p.state == p.written {
+		return
+	}
+
+	p.buf[0] = p.state
+	if p.bus.Tx(p.address, p.buf[:], nil) == nil {
+		p.written = p.state
+		p.sent = true
+	}
+}
+
+// pcf8574Pin is a pin of a PCF8574, its level is written by Flush.
+type pcf8574Pin struct {
+	expander *PCF8574
+	mask     uint8
+}
+
+// Configure does nothing, the pins of a PCF8574 are always outputs.
+func (p pcf8574Pin) Configure(config PinConfig) {}
+
+// High sets the pin high with the next Flush.
+func (p pcf8574Pin) High() {
+	p.expander.state |= p.mask
+}
+
+// Low sets the pin low with the next Flush.
+func (p pcf8574Pin) Low() {
+	p.expander.state &^= p.mask
+}
+
+// Bank returns the expander of the pin.
+func (p pcf8574Pin) Bank() PinBank {
+	return p.expander
+}
@@ -0,0 +1,94 @@
+package sevseg
+
+import "slices"
+
+// OutputPin is an output pin driving a digit or segment line. machine.Pin
+// satisfies it, so do pins of GPIO expanders or test doubles.
+//
+// The timer of HardwarePWM and the port fast path of the segment pins
+// require machine.Pin.
+type OutputPin interface {
+	Configure(config PinConfig)
+	High()
+	Low()
+}
+
+// InputPin is a pin which can be read, like the strapping pins of
+// SelectProfile. machine.Pin satisfies it.
+type InputPin interface {
+	Configure(config PinConfig)
+	Get() bool
+}
+
+// setPin drives the pin high or low.
+func setPin(pin OutputPin, high bool) {
+	if high {
+		pin.High()
+	} else {
+		pin.Low()
+	}
+}
+
+// missingPins reports whether a pin required by the configuration is nil.
+// Unlike machine.Pin, an OutputPin has no usable zero value.
+func missingPins(cfg Config) bool {
+	if slices.Contains(cfg.DigitPins, nil) || slices.Contains(cfg.AuxDigitPins, nil) {
+		return true
+	}
+
+	switch {
+	case cfg.ColonSegments|cfg.ApostropheSegments != 0 && cfg.ClockIndicatorPin == nil:
+		return true
+	case cfg.SignMinusSegments != 0 && cfg.SignDigitPin == nil:
+		return true
+	case cfg.ShiftRegister == NoShiftRegister:
+		return slices.Contains(cfg.SegmentPins, nil) ||
+			slices.Contains(cfg.GreenSegmentPins, nil) || slices.Contains(cfg.BlueSegmentPins, nil)
+	case cfg.ShiftRegister == ShiftRegister74HC595 && cfg.ShiftLatchPin == nil:
+		return true
+	}
+
+	return cfg.ShiftSPI == nil && (cfg.ShiftDataPin == nil || cfg.ShiftClockPin == nil)
+}
+
+// PinBank is a group of pins which are written together, e.g. the port of an
+// I2C GPIO expander. High and Low of its pins only change a shadow register,
+// Flush writes the register if it changed. The display flushes the banks of
+// its pins after each step of Refresh, so a step takes one bus transaction
+// per bank instead of one per pin.
+type PinBank interface {
+	Flush()
+}
+
+// BankPin is an OutputPin belonging to a PinBank.
+type BankPin interface {
+	OutputPin
+	Bank() PinBank
+}
+
+// appendBanks appends the banks of the pins to banks, each bank once.
+func appendBanks(banks []PinBank, pins []OutputPin) []PinBank {
+	for _, pin := range pins {
+		bankPin, ok := pin.(BankPin)
+		if !ok || slices.Contains(banks, bankPin.Bank()) {
+			continue
+		}
+
+		banks = append(banks, bankPin.Bank())
+	}
+
+	return banks
+}
+
+// bankedShiftPins reports whether a pin of the segment shift register belongs
+// to a PinBank. The shift register is bit-banged without flushing, so its
+// pins must be written immediately.
+func bankedShiftPins(cfg Config) bool {
+	for _, pin := range []OutputPin{cfg.ShiftDataPin, cfg.ShiftClockPin, cfg.ShiftLatchPin} {
+		if _, ok := pin.(BankPin); ok {
+			return true
+		}
+	}
+
+	return false
+}
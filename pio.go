@@ -0,0 +1,83 @@
+package sevseg
+
+type refreshEngine uint8
+
+// RefreshCPU multiplexes the digits in Refresh, which is the default.
+// RefreshPIO multiplexes them in a PIO state machine of the RP2040 or RP2350,
+// fed with the frame by DMA, so the CPU is free apart from Refresh calls for
+// effects.
+const (
+	RefreshCPU refreshEngine = iota
+	RefreshPIO
+)
+
+// pioMaxDigits is the number of digits the PIO refresh engine supports.
+const pioMaxDigits = 8
+
+// pioSlotMicros is the time each digit is shown per frame by the PIO refresh
+// engine, giving a refresh rate of 250Hz with 4 digits.
+const pioSlotMicros = 1000
+
+// pioDeadMicros is the time all digits are off between two digits, which
+// avoids ghosting on the next digit.
+const pioDeadMicros = 10
+
+// newPIOSevSeg creates a display multiplexed by the PIO refresh engine. Like
+// a controller, the engine gets the content when it changes, Refresh is only
+// needed for effects.
+func newPIOSevSeg(cfg Config) (*SevSeg, error) {
+	// The engine drives plain digit and segment lines, one GPIO each
+	if len(cfg.DigitPins) == 0 || len(cfg.DigitPins) > pioMaxDigits ||
+		len(cfg.SegmentPins) < 7 || len(cfg.SegmentPins) > 8 ||
+		cfg.ShiftRegister != NoShiftRegister || cfg.Decoder != NoDecoder || cfg.PWMType != SoftwarePWM ||
+		cfg.ScanMode != ScanDigits || len(cfg.AuxDigitPins) > 0 ||
+		len(cfg.GreenSegmentPins) > 0 || len(cfg.BlueSegmentPins) > 0 ||
+		cfg.ColonSegments|cfg.ApostropheSegments|cfg.SignMinusSegments != 0 ||
+		cfg.ColonPin != nil || cfg.ApostrophePin != nil || cfg.ButtonCommonPin != nil {
+		return nil, ErrInvalidConfig
+	}
+
+	s := newSevSeg(cfg, cfg.DigitPins)
+
+	engine, err := newPIOEngine(s.digitPins, s.segmentPins, s.config == CommonAnode)
+	if err != nil {
+		return nil, err
+	}
+
+	s.backend = engine
+	s.pioRefresh = true
+	s.backendFrame = make([]uint8, len(s.digitPins))
+	s.liveDisplay = s.updatedDisplay // Sent right away, see stageFrame
+
+	s.Clear()
+
+	return s, nil
+}
+
+// pioFrameWords returns the words of a frame for the PIO refresh engine:
+// the levels of the pins and the number of ticks to hold them, once for the
+// lit digit and once for the dead time before the next one. offLevels are the
+// levels turning all digits and segments off, the masks select the pin of
+// each digit and segment.
+func pioFrameWords(words []uint32, frame []uint8, brightness uint8, offLevels uint32, digitMasks []uint32, segmentMasks []uint32) {
+	onTicks := uint32(pioSlotMicros-pioDeadMicros) * uint32(brightness) / 100
+	offTicks := uint32(pioSlotMicros) - onTicks
+
+	for digit, pattern := range frame {
+		levels := offLevels
+		if brightness > 0 {
+			// Toggling the off level of a line turns it on
+			levels ^= digitMasks[digit]
+			for segment, mask := range segmentMasks {
+				if pattern&(1<<segment) != 0 {
+					levels ^= mask
+				}
+			}
+		}
+
+		words[4*digit] = levels
+		words[4*digit+1] = onTicks
+		words[4*digit+2] = offLevels
+		words[4*digit+3] = offTicks
+	}
+}
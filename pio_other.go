@@ -0,0 +1,13 @@
+//go:build !tinygo || !(rp2040 || rp2350)
+
+package sevseg
+
+// newPIOEngine returns ErrInvalidConfig, as the target has no PIO.
+func newPIOEngine(digitPins, segmentPins []OutputPin, commonAnode bool) (backend, error) {
+	return nil, ErrInvalidConfig
+}
+
+// pioEngineSize returns 0, as the target has no PIO.
+func pioEngineSize(digits, segments int) uintptr {
+	return 0
+}
@@ -0,0 +1,213 @@
+//go:build tinygo && (rp2040 || rp2350)
+
+package sevseg
+
+import (
+	"device/rp"
+	"machine"
+	"runtime/volatile"
+	"unsafe"
+)
+
+// The PIO refresh engine uses state machine 0 of PIO1 and the DMA channels 10
+// (data) and 11 (reload), which are otherwise unused by TinyGo, so PIO0 stays
+// free for drivers like WS2812. The RP2040 and the RP2350 share the layout of
+// these blocks, apart from the fields of the DMA CTRL register, see
+// pio_rp2040.go and pio_rp2350.go.
+const (
+	pioDataDREQ      = 8 // DREQ_PIO1_TX0
+	pioReloadChannel = 11
+)
+
+// Fields of the CTRL register of a DMA channel at the same position on both
+// chips
+const (
+	dmaEnable    = 1 << 0
+	dmaWords     = 2 << 2 // DATA_SIZE of 32 bits
+	dmaIncrRead  = 1 << 4
+	dmaPermanent = 0x3f // TREQ_SEL of an unpaced transfer
+)
+
+// pioProgram shows the frame fed by DMA: it drives the pins with the levels
+// of a word and holds them for the ticks of the following word (plus 3 ticks
+// for the instructions). The FIFO is refilled automatically.
+var pioProgram = [...]uint16{
+	0x6000, // out pins, 32
+	0x6020, // out x, 32
+	0x0042, // jmp x--, 2
+}
+
+// pioEngine multiplexes the digits in a PIO state machine. A DMA channel
+// sends the words of the frame to the state machine, a second channel
+// restarts it with the buffer in start when it's done. The frame is triple
+// buffered: the next frame is written to a buffer neither being sent nor
+// queued in start, so a frame is never shown half written.
+type pioEngine struct {
+	base         uint32 // GPIO of the lowest pin, bit 0 of the levels
+	offLevels    uint32
+	digitMasks   []uint32
+	segmentMasks []uint32
+	frame        []uint8
+	brightness   uint8
+
+	// A spare word after each buffer keeps the end of one buffer from being
+	// the start of the next one
+	buffers [3][4*pioMaxDigits + 1]uint32
+	start   uint32 // Address of the buffer the next frame is sent from
+	words   uint32 // Words per frame
+}
+
+// newPIOEngine starts the PIO refresh engine on the pins. All pins must be
+// GPIOs, their levels are driven by the state machine from now on.
+func newPIOEngine(digitPins, segmentPins []OutputPin, commonAnode bool) (backend, error) {
+	pins := make([]machine.Pin, 0, len(digitPins)+len(segmentPins))
+	for _, outputPin := range append(digitPins[:len(digitPins):len(digitPins)], segmentPins...) {
+		pin, ok := outputPin.(machine.Pin)
+		if !ok || pin >= 30 {
+			return nil, ErrInvalidConfig // E.g. a pin of an I/O expander
+		}
+
+		pins = append(pins, pin)
+	}
+
+	e := &pioEngine{
+		base:         uint32(pins[0]),
+		digitMasks:   make([]uint32, len(digitPins)),
+		segmentMasks: make([]uint32, len(segmentPins)),
+		frame:        make([]uint8, len(digitPins)),
+		words:        uint32(4 * len(digitPins)),
+	}
+
+	for _, pin := range pins {
+		e.base = min(e.base, uint32(pin))
+	}
+
+	// Common anode displays have active high digit lines and active low
+	// segment lines, common cathode displays the other way around
+	for i, pin := range pins[:len(digitPins)] {
+		e.digitMasks[i] = 1 << (uint32(pin) - e.base)
+		if !commonAnode {
+			e.offLevels |= e.digitMasks[i]
+		}
+	}
+
+	for i, pin := range pins[len(digitPins):] {
+		e.segmentMasks[i] = 1 << (uint32(pin) - e.base)
+		if commonAnode {
+			e.offLevels |= e.segmentMasks[i]
+		}
+	}
+
+	e.fill(&e.buffers[0])
+	e.start = e.address(0)
+	e.run(pins)
+
+	return e, nil
+}
+
+// pioEngineSize returns the RAM the engine takes for the given number of
+// digits and segments, see MemoryFootprint.
+func pioEngineSize(digits, segments int) uintptr {
+	return unsafe.Sizeof(pioEngine{}) + uintptr(4*(digits+segments)+digits)
+}
+
+// run sets up the state machine and the DMA channels and starts them.
+func (e *pioEngine) run(pins []machine.Pin) {
+	// Take PIO1 and the DMA out of reset
+	const blocks = rp.RESETS_RESET_PIO1 | rp.RESETS_RESET_DMA
+	rp.RESETS.RESET.ClearBits(blocks)
+	for rp.RESETS.RESET_DONE.Get()&blocks != blocks {
+	}
+
+	pio := rp.PIO1
+	instrMem := [len(pioProgram)]*volatile.Register32{&pio.INSTR_MEM0, &pio.INSTR_MEM1, &pio.INSTR_MEM2}
+	for i, instr := range pioProgram {
+		instrMem[i].Set(uint32(instr))
+	}
+
+	// One tick per microsecond
+	pio.SM0_CLKDIV.Set(machine.CPUFrequency() / 1e6 << 16)
+
+	// Wrap from the last instruction to the first one, refill the FIFO
+	// automatically after 32 bits shifted out to the right, and join the
+	// FIFOs for a deeper TX FIFO
+	pio.SM0_EXECCTRL.Set(uint32(len(pioProgram)-1) << 12)
+	pio.SM0_SHIFTCTRL.Set(1<<30 | 1<<19 | 1<<17)
+
+	// Make the pins outputs one by one with set pindirs, 1
+	for _, pin := range pins {
+		pio.SM0_PINCTRL.Set(1<<26 | uint32(pin)<<5)
+		pio.SM0_INSTR.Set(0xe081)
+	}
+	pio.SM0_PINCTRL.Set(32<<20 | e.base)
+
+	for _, pin := range pins {
+		pin.Configure(machine.PinConfig{Mode: machine.PinPIO1})
+	}
+
+	// The reload channel writes start to the read address of the data
+	// channel, which restarts it, whenever the data channel is done
+	dma := rp.DMA
+
+	dma.CH11_READ_ADDR.Set(pioAddress(unsafe.Pointer(&e.start)))
+	dma.CH11_WRITE_ADDR.Set(pioAddress(unsafe.Pointer(&dma.CH10_AL3_READ_ADDR_TRIG)))
+	dma.CH11_TRANS_COUNT.Set(1)
+	dma.CH11_AL1_CTRL.Set(dmaPermanent<<dmaTreqSel | pioReloadChannel<<dmaChainTo | dmaWords | dmaEnable)
+
+	dma.CH10_READ_ADDR.Set(e.start)
+	dma.CH10_WRITE_ADDR.Set(pioAddress(unsafe.Pointer(&pio.TXF0)))
+	dma.CH10_TRANS_COUNT.Set(e.words)
+	dma.CH10_CTRL_TRIG.Set(pioDataDREQ<<dmaTreqSel | pioReloadChannel<<dmaChainTo | dmaIncrRead | dmaWords | dmaEnable)
+
+	pio.SM0_INSTR.Set(0x0000) // jmp 0
+	pio.CTRL.Set(1)           // Enable state machine 0
+}
+
+// writeFrame queues the frame, it's shown from the start of the next frame.
+func (e *pioEngine) writeFrame(frame []uint8) {
+	copy(e.frame, frame)
+	e.queue()
+}
+
+// setBrightness sets the share of the slot each digit is lit, 0 turns the
+// display off.
+func (e *pioEngine) setBrightness(brightness uint8) {
+	if brightness == e.brightness {
+		return
+	}
+
+	e.brightness = brightness
+	e.queue()
+}
+
+// queue writes the frame to a free buffer and queues it for the next frame.
+func (e *pioEngine) queue() {
+	reading := rp.DMA.CH10_READ_ADDR.Get()
+	for i := range e.buffers {
+		address := e.address(i)
+		if address == e.start || reading >= address && reading <= address+4*e.words {
+			continue
+		}
+
+		e.fill(&e.buffers[i])
+		volatile.StoreUint32(&e.start, address)
+
+		return
+	}
+}
+
+// fill writes the words of the frame to a buffer.
+func (e *pioEngine) fill(buffer *[4*pioMaxDigits + 1]uint32) {
+	pioFrameWords(buffer[:e.words], e.frame, e.brightness, e.offLevels, e.digitMasks, e.segmentMasks)
+}
+
+// address returns the address of a buffer.
+func (e *pioEngine) address(i int) uint32 {
+	return pioAddress(unsafe.Pointer(&e.buffers[i]))
+}
+
+// pioAddress returns the bus address of a register or buffer, as used by the
+// DMA.
+func pioAddress(pointer unsafe.Pointer) uint32 {
+	return uint32(uintptr(pointer))
+}
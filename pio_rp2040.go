@@ -0,0 +1,10 @@
+//go:build tinygo && rp2040
+
+package sevseg
+
+// Positions of the CHAIN_TO and TREQ_SEL fields of the CTRL register of a DMA
+// channel on the RP2040
+const (
+	dmaChainTo = 11
+	dmaTreqSel = 15
+)
@@ -0,0 +1,11 @@
+//go:build tinygo && rp2350
+
+package sevseg
+
+// Positions of the CHAIN_TO and TREQ_SEL fields of the CTRL register of a DMA
+// channel on the RP2350, two bits further up than on the RP2040 due to the
+// new INCR_READ_REV and INCR_WRITE_REV bits
+const (
+	dmaChainTo = 13
+	dmaTreqSel = 17
+)
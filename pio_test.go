@@ -0,0 +1,44 @@
+//go:build !tinygo
+
+package sevseg
+
+import "testing"
+
+func TestPIOFrameWords(t *testing.T) {
+	// A common anode display: digits on bits 0 and 1 (active high),
+	// segments A and B on bits 2 and 3 (active low)
+	digitMasks := []uint32{0b0001, 0b0010}
+	segmentMasks := []uint32{0b0100, 0b1000}
+	offLevels := uint32(0b1100)
+
+	words := make([]uint32, 8)
+	pioFrameWords(words, []uint8{0b01, 0b11}, 100, offLevels, digitMasks, segmentMasks)
+
+	want := []uint32{
+		0b1001, pioSlotMicros - pioDeadMicros, offLevels, pioDeadMicros,
+		0b0010, pioSlotMicros - pioDeadMicros, offLevels, pioDeadMicros,
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("word %d = %#b, want %#b", i, words[i], want[i])
+		}
+	}
+
+	pioFrameWords(words, []uint8{0b01, 0b11}, 0, offLevels, digitMasks, segmentMasks)
+	for i := 0; i < len(words); i += 2 {
+		if words[i] != offLevels {
+			t.Errorf("brightness 0: word %d = %#b, want off levels %#b", i, words[i], offLevels)
+		}
+	}
+}
+
+func TestPIORefreshNeedsPIO(t *testing.T) {
+	_, err := NewSevSeg(Config{
+		DigitPins:     SimulatedPins(4),
+		SegmentPins:   SimulatedPins(8),
+		RefreshEngine: RefreshPIO,
+	})
+	if err != ErrInvalidConfig {
+		t.Errorf("NewSevSeg() = %v, want %v on the host", err, ErrInvalidConfig)
+	}
+}
@@ -0,0 +1,143 @@
+package sevseg
+
+// PlayFrames replays a sequence of frames as a looping animation, e.g. frames
+// captured with StreamFrames or FrameLiteral. Each frame is in the format of
+// SetSegment and is shown for ticksPerFrame ticks of the effects clock, i.e.
+// full frames of Refresh. Digits not covered by a frame are blank.
+//
+// The frames are referenced, not copied. Note that the slice headers of a
+// [][]uint8 literal still live in RAM; use PlayFrameString to keep the whole
+// animation in flash.
+// Setting any other content stops the playback.
+func (s *SevSeg) PlayFrames(frames [][]uint8, ticksPerFrame uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if len(frames) == 0 || ticksPerFrame == 0 {
+		return ErrInvalidArgument
+	}
+
+	for _, frame := range frames {
+		if len(frame) > len(s.digitPins) {
+			return ErrTooManyDigits
+		}
+
+		if err := s.checkDecimalPoints(frame); err != nil {
+			return err
+		}
+	}
+
+	s.playFrames = frames
+	s.playString = ""
+	s.startPlayback(ticksPerFrame)
+
+	return nil
+}
+
+// PlayFrameString is like PlayFrames, but takes the frames concatenated into
+// a single string of frameWidth bytes per frame. String constants are
+// immutable, so TinyGo can keep them in flash, and the frames are read in
+// place. An animation thus takes no RAM beyond the string header, which
+// matters on targets like the ATmega328P with only 2 KB of RAM:
+//
+//	// Runs a segment around the edge of a 2-digit display
+//	const spinner = "\x00\x01" + "\x01\x00" + "\x02\x00" + "\x04\x00" +
+//		"\x08\x00" + "\x00\x08" + "\x00\x10" + "\x00\x20"
+//
+//	display.PlayFrameString(spinner, 2, 20)
+func (s *SevSeg) PlayFrameString(frames string, frameWidth int, ticksPerFrame uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if frameWidth <= 0 || len(frames) == 0 || len(frames)%frameWidth != 0 || ticksPerFrame == 0 {
+		return ErrInvalidArgument
+	}
+
+	if frameWidth > len(s.digitPins) {
+		return ErrTooManyDigits
+	}
+
+	for i := range len(frames) {
+		if err := s.checkDecimalPoint(frames[i]); err != nil {
+			return err
+		}
+	}
+
+	s.playFrames = nil
+	s.playString = frames
+	s.playWidth = frameWidth
+	s.startPlayback(ticksPerFrame)
+
+	return nil
+}
+
+// StopFrames stops the playback or the character map and keeps the current
+// frame on the display.
+func (s *SevSeg) StopFrames() {
+	s.lock()
+	defer s.unlock()
+
+	s.stopFrames()
+}
+
+// stopFrames stops the playback, also called when new content is committed.
+func (s *SevSeg) stopFrames() {
+	s.playFrames = nil
+	s.playString = ""
+	s.charMapPeriod = 0
+}
+
+// startPlayback shows the first frame of the playback.
+func (s *SevSeg) startPlayback(ticksPerFrame uint16) {
+	s.charMapPeriod = 0 // The playback replaces the character map
+	s.playPeriod = ticksPerFrame
+	s.playNext = s.deadline(ticksPerFrame)
+	s.playIndex = 0
+	s.showPlayFrame()
+}
+
+// playing reports whether frames are being played.
+func (s *SevSeg) playing() bool {
+	return s.playFrames != nil || s.playString != ""
+}
+
+// playFrameCount returns the number of frames being played.
+func (s *SevSeg) playFrameCount() int {
+	if s.playString != "" {
+		return len(s.playString) / s.playWidth
+	}
+
+	return len(s.playFrames)
+}
+
+// advancePlayback advances the playback by one tick of the effects clock.
+func (s *SevSeg) advancePlayback() {
+	if !s.playing() {
+		return
+	}
+
+	if s.expired(&s.playNext, s.playPeriod) {
+		s.playIndex = (s.playIndex + 1) % s.playFrameCount()
+		s.showPlayFrame()
+	}
+}
+
+// showPlayFrame commits the current frame of the playback.
+func (s *SevSeg) showPlayFrame() {
+	for i := range s.updatedDisplay {
+		s.updatedDisplay[i] = s.getSegmentCode(36) // BLANK
+	}
+
+	if s.playString != "" {
+		offset := s.playIndex * s.playWidth
+		for i := range s.playWidth {
+			s.updatedDisplay[i] = s.playString[offset+i]
+		}
+	} else {
+		copy(s.updatedDisplay, s.playFrames[s.playIndex])
+	}
+
+	s.maskDecimalPoints(s.updatedDisplay)
+
+	s.commit(ContentAnimation)
+}
@@ -0,0 +1,67 @@
+//go:build tinygo && (sam || nrf || stm32 || rp2040 || esp32)
+
+package sevseg
+
+import (
+	"machine"
+	"runtime/volatile"
+)
+
+// segmentPort writes all segment lines with a single access to the set and
+// clear registers of their GPIO port, instead of one Pin.Set per segment.
+// It's only used if all segment pins are on the same port.
+type segmentPort struct {
+	set        *uint32
+	clear      *uint32
+	setMasks   [8]uint32
+	clearMasks [8]uint32
+}
+
+// newSegmentPort detects whether all pins are on the same GPIO port. Returns
+// false if they aren't, the pins must be written one by one then.
+func newSegmentPort(pins []OutputPin) (segmentPort, bool) {
+	var p segmentPort
+	if len(pins) == 0 || len(pins) > len(p.setMasks) {
+		return p, false
+	}
+
+	for i, outputPin := range pins {
+		pin, ok := outputPin.(machine.Pin)
+		if !ok {
+			return segmentPort{}, false // E.g. a pin of an I/O expander
+		}
+
+		set, setMask := pin.PortMaskSet()
+		clear, clearMask := pin.PortMaskClear()
+		if i == 0 {
+			p.set, p.clear = set, clear
+		} else if set != p.set || clear != p.clear {
+			return segmentPort{}, false
+		}
+
+		p.setMasks[i] = setMask
+		p.clearMasks[i] = clearMask
+	}
+
+	return p, true
+}
+
+// write sets the segment lines to the pattern, bit 0 being the first pin.
+// The pattern is inverted for active low segment lines.
+func (p *segmentPort) write(pattern uint8, activeLow bool) {
+	if activeLow {
+		pattern = ^pattern
+	}
+
+	var set, clear uint32
+	for i := range p.setMasks {
+		if pattern&(1<<i) != 0 {
+			set |= p.setMasks[i]
+		} else {
+			clear |= p.clearMasks[i]
+		}
+	}
+
+	volatile.StoreUint32(p.clear, clear)
+	volatile.StoreUint32(p.set, set)
+}
@@ -0,0 +1,67 @@
+//go:build tinygo && avr
+
+package sevseg
+
+import (
+	"machine"
+	"runtime/interrupt"
+	"runtime/volatile"
+)
+
+// segmentPort writes all segment lines with a single store to the PORT
+// register of their GPIO port, instead of one Pin.Set per segment. AVR has no
+// set and clear registers, so the other pins of the port are preserved with a
+// read-modify-write, during which interrupts are disabled. It's only used if
+// all segment pins are on the same port.
+type segmentPort struct {
+	port  *volatile.Register8
+	mask  uint8 // Bits of all segment pins
+	masks [8]uint8
+}
+
+// newSegmentPort detects whether all pins are on the same GPIO port. Returns
+// false if they aren't, the pins must be written one by one then.
+func newSegmentPort(pins []OutputPin) (segmentPort, bool) {
+	var p segmentPort
+	if len(pins) == 0 || len(pins) > len(p.masks) {
+		return p, false
+	}
+
+	for i, outputPin := range pins {
+		pin, ok := outputPin.(machine.Pin)
+		if !ok {
+			return segmentPort{}, false // E.g. a pin of an I/O expander
+		}
+
+		port, mask := pin.PortMaskSet()
+		if i == 0 {
+			p.port = port
+		} else if port != p.port {
+			return segmentPort{}, false
+		}
+
+		p.masks[i] = mask
+		p.mask |= mask
+	}
+
+	return p, true
+}
+
+// write sets the segment lines to the pattern, bit 0 being the first pin.
+// The pattern is inverted for active low segment lines.
+func (p *segmentPort) write(pattern uint8, activeLow bool) {
+	if activeLow {
+		pattern = ^pattern
+	}
+
+	var set uint8
+	for i := range p.masks {
+		if pattern&(1<<i) != 0 {
+			set |= p.masks[i]
+		}
+	}
+
+	state := interrupt.Disable()
+	p.port.Set(p.port.Get()&^p.mask | set)
+	interrupt.Restore(state)
+}
@@ -0,0 +1,15 @@
+//go:build !tinygo || !(sam || nrf || stm32 || rp2040 || esp32 || avr)
+
+package sevseg
+
+// segmentPort is unused on targets without direct access to the registers of a
+// whole GPIO port, the segment pins are written one by one.
+type segmentPort struct{}
+
+// newSegmentPort always returns false, as the target has no port fast path.
+func newSegmentPort(pins []OutputPin) (segmentPort, bool) {
+	return segmentPort{}, false
+}
+
+// write is never called, as newSegmentPort never succeeds.
+func (p *segmentPort) write(pattern uint8, activeLow bool) {}
@@ -0,0 +1,51 @@
+//go:build tinygo && arduino_nano
+
+package presets
+
+import (
+	"machine"
+
+	"github.com/domi413/sevseg"
+)
+
+// NanoTwoDigitCC returns the configuration of a 2-digit common-cathode
+// display connected to an Arduino Nano:
+//
+//	Digits (left to right): D3, D2
+//	Segments A-G, DP:       D4 - D11
+func NanoTwoDigitCC() sevseg.Config {
+	return sevseg.Config{
+		Hardware: sevseg.CommonCathode,
+		DigitPins: []sevseg.OutputPin{
+			machine.D3,
+			machine.D2,
+		},
+		SegmentPins: []sevseg.OutputPin{
+			machine.D4,  // A
+			machine.D5,  // B
+			machine.D6,  // C
+			machine.D7,  // D
+			machine.D8,  // E
+			machine.D9,  // F
+			machine.D10, // G
+			machine.D11, // DP
+		},
+	}
+}
+
+// NanoFourDigitCC returns the configuration of a common 4-digit
+// common-cathode clock module (e.g. 5641AS) connected to an Arduino Nano:
+//
+//	Digits (left to right): D13, D12, D3, D2
+//	Segments A-G, DP:       D4 - D11
+func NanoFourDigitCC() sevseg.Config {
+	cfg := NanoTwoDigitCC()
+	cfg.DigitPins = []sevseg.OutputPin{
+		machine.D13,
+		machine.D12,
+		machine.D3,
+		machine.D2,
+	}
+
+	return cfg
+}
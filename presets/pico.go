@@ -0,0 +1,46 @@
+//go:build tinygo && (pico || pico2)
+
+package presets
+
+import (
+	"machine"
+
+	"github.com/domi413/sevseg"
+)
+
+// PicoFourDigitCC returns the configuration of a 4-digit common-cathode
+// display connected to a Raspberry Pi Pico (2):
+//
+//	Digits (left to right): GP0 - GP3
+//	Segments A-G, DP:       GP4 - GP11
+func PicoFourDigitCC() sevseg.Config {
+	return sevseg.Config{
+		Hardware: sevseg.CommonCathode,
+		DigitPins: []sevseg.OutputPin{
+			machine.GP0,
+			machine.GP1,
+			machine.GP2,
+			machine.GP3,
+		},
+		SegmentPins: []sevseg.OutputPin{
+			machine.GP4,  // A
+			machine.GP5,  // B
+			machine.GP6,  // C
+			machine.GP7,  // D
+			machine.GP8,  // E
+			machine.GP9,  // F
+			machine.GP10, // G
+			machine.GP11, // DP
+		},
+	}
+}
+
+// PicoFourDigitCA returns the configuration of a common 4-digit common-anode
+// clock module (e.g. 5641BS) connected to a Raspberry Pi Pico (2). The wiring
+// is the same as for PicoFourDigitCC.
+func PicoFourDigitCA() sevseg.Config {
+	cfg := PicoFourDigitCC()
+	cfg.Hardware = sevseg.CommonAnode
+
+	return cfg
+}
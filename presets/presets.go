@@ -0,0 +1,12 @@
+//go:build tinygo
+
+// Package presets provides ready-made sevseg configurations for popular
+// combinations of boards and displays.
+//
+// Each preset documents the wiring it expects. The returned Config can be
+// adjusted before it's passed to sevseg.NewSevSeg, e.g.:
+//
+//	cfg := presets.NanoTwoDigitCC()
+//	cfg.UseLeadingZeros = true
+//	display, err := sevseg.NewSevSeg(cfg)
+package presets
@@ -0,0 +1,85 @@
+package sevseg
+
+import "slices"
+
+// profile is a named display configuration.
+type profile struct {
+	name   string
+	config Config
+}
+
+// profiles holds all registered profiles.
+var profiles []profile
+
+// RegisterProfile registers a named configuration, so one firmware image can
+// support several board revisions with different display wiring.
+//
+// Returns ErrInvalidArgument if the name is empty or ErrDuplicateProfile if
+// it's already registered.
+func RegisterProfile(name string, cfg Config) error {
+	if name == "" {
+		return ErrInvalidArgument
+	}
+
+	if _, ok := lookupProfile(name); ok {
+		return ErrDuplicateProfile
+	}
+
+	profiles = append(profiles, profile{name: name, config: cfg})
+
+	return nil
+}
+
+// NewSevSegFromProfile creates a new instance of sevSeg with the configuration
+// registered under the given name.
+//
+// Returns ErrUnknownProfile if no such profile has been registered.
+func NewSevSegFromProfile(name string) (*SevSeg, error) {
+	cfg, ok := lookupProfile(name)
+	if !ok {
+		return nil, ErrUnknownProfile
+	}
+
+	return NewSevSeg(cfg)
+}
+
+// SelectProfile reads the strapping pins at boot and returns the profile name
+// selected by them.
+//
+// The pins are read with the internal pull-up enabled and form a binary index
+// into names, the first pin being the LSB. A pin pulled to ground reads as 0.
+// E.g. with two strapping pins, up to four board revisions can be told apart.
+//
+// Returns ErrInvalidArgument if a pin is nil or ErrUnknownProfile if no name
+// exists for the index read.
+func SelectProfile(strapPins []InputPin, names []string) (string, error) {
+	if slices.Contains(strapPins, nil) {
+		return "", ErrInvalidArgument
+	}
+
+	index := 0
+	for i, pin := range strapPins {
+		pin.Configure(PinConfig{Mode: machinePinInputPullup})
+
+		if pin.Get() {
+			index |= 1 << i
+		}
+	}
+
+	if index >= len(names) {
+		return "", ErrUnknownProfile
+	}
+
+	return names[index], nil
+}
+
+// lookupProfile returns the configuration registered under the given name.
+func lookupProfile(name string) (Config, bool) {
+	for _, p := range profiles {
+		if p.name == name {
+			return p.config, true
+		}
+	}
+
+	return Config{}, false
+}
@@ -0,0 +1,57 @@
+package sevseg
+
+import (
+	"errors"
+	"testing"
+)
+
+// strapPin is a strapping pin read by SelectProfile.
+type strapPin struct {
+	level  bool
+	pullup bool
+}
+
+func (p *strapPin) Configure(config PinConfig) {
+	p.pullup = config.Mode == machinePinInputPullup
+}
+
+func (p *strapPin) Get() bool {
+	return p.level
+}
+
+func TestSelectProfile(t *testing.T) {
+	names := []string{"rev-a", "rev-b", "rev-c"}
+
+	tests := []struct {
+		levels []bool
+		want   string
+		err    error
+	}{
+		{[]bool{false, false}, "rev-a", nil},
+		{[]bool{true, false}, "rev-b", nil},
+		{[]bool{false, true}, "rev-c", nil},
+		{[]bool{true, true}, "", ErrUnknownProfile},
+	}
+
+	for _, tt := range tests {
+		pins := make([]InputPin, len(tt.levels))
+		for i, level := range tt.levels {
+			pins[i] = &strapPin{level: level}
+		}
+
+		name, err := SelectProfile(pins, names)
+		if name != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("levels %v: got %q, %v, want %q, %v", tt.levels, name, err, tt.want, tt.err)
+		}
+
+		for i, pin := range pins {
+			if !pin.(*strapPin).pullup {
+				t.Errorf("levels %v: pin %d read without pull-up", tt.levels, i)
+			}
+		}
+	}
+
+	if _, err := SelectProfile([]InputPin{nil}, names); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("nil pin: err = %v, want ErrInvalidArgument", err)
+	}
+}
@@ -0,0 +1,81 @@
+package sevseg
+
+import "time"
+
+// FrameStats describes how often Refresh has been called during the last
+// second, see GetFrameStats.
+type FrameStats struct {
+	CallRate    uint32        // Refresh calls per second
+	FrameRate   uint32        // Full frames per second, i.e. how often each digit is lit
+	MinInterval time.Duration // Shortest time between two Refresh calls
+	MaxInterval time.Duration // Longest time between two Refresh calls, e.g. caused by a blocking loop
+}
+
+// GetRefreshRate returns the measured number of full frames per second, see
+// GetFrameStats. It should stay above about 100 to avoid visible flicker.
+func (s *SevSeg) GetRefreshRate() uint32 {
+	s.lock()
+	defer s.unlock()
+
+	return s.GetFrameStats().FrameRate
+}
+
+// GetFrameStats returns how often Refresh has actually been called during
+// the last full second, measured with the system clock, so the main loop can
+// be tuned to stay above the flicker threshold of about 100 frames per
+// second. With SoftwarePWM and a brightness below 100, each digit only
+// flashes once every PWMSteps frames, so the frame rate must be accordingly
+// higher.
+//
+// The measurement starts with the first call, so Refresh has no overhead
+// unless the stats are used. Until a second has passed, the stats are zero.
+func (s *SevSeg) GetFrameStats() FrameStats {
+	s.lock()
+	defer s.unlock()
+
+	if !s.statsEnabled {
+		s.statsEnabled = true
+		s.statsStart = time.Now()
+	}
+
+	return s.frameStats
+}
+
+// recordRefreshCall updates the measurement of GetFrameStats with a call of
+// Refresh.
+func (s *SevSeg) recordRefreshCall() {
+	if !s.statsEnabled {
+		return
+	}
+
+	now := time.Now()
+	if !s.statsLast.IsZero() {
+		interval := now.Sub(s.statsLast)
+		if s.statsMin == 0 || interval < s.statsMin {
+			s.statsMin = interval
+		}
+
+		s.statsMax = max(s.statsMax, interval)
+	}
+
+	s.statsLast = now
+	s.statsCalls++
+
+	elapsed := now.Sub(s.statsStart)
+	if elapsed < time.Second {
+		return
+	}
+
+	s.frameStats = FrameStats{
+		CallRate:    uint32(uint64(s.statsCalls) * uint64(time.Second) / uint64(elapsed)),
+		FrameRate:   uint32(uint64(s.statsFrames) * uint64(time.Second) / uint64(elapsed)),
+		MinInterval: s.statsMin,
+		MaxInterval: s.statsMax,
+	}
+
+	s.statsStart = now
+	s.statsCalls = 0
+	s.statsFrames = 0
+	s.statsMin = 0
+	s.statsMax = 0
+}
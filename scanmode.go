@@ -0,0 +1,41 @@
+package sevseg
+
+type scanMode uint8
+
+// ScanDigits and ScanSegments define how Refresh multiplexes the display.
+// ScanDigits lights one digit with all its segments per step. ScanSegments
+// lights one segment line across all digits showing it per step, which suits
+// wirings where the segment lines are switched by drivers and high-current
+// displays, as each digit line only carries the current of one segment.
+const (
+	ScanDigits scanMode = iota
+	ScanSegments
+)
+
+// scanSteps returns the number of Refresh steps of a frame, i.e. the slots
+// or, with ScanSegments, the segment lines.
+func (s *SevSeg) scanSteps() uint8 {
+	if s.scanSegments {
+		return s.segmentCount()
+	}
+
+	return s.slotCount()
+}
+
+// lightSegmentLine turns on the segment line of the current step and the
+// digits (and aux LED groups) showing it.
+func (s *SevSeg) lightSegmentLine() {
+	line := s.currentDigitToRefresh
+
+	for i, pin := range s.segmentPins {
+		s.setSegmentPin(pin, uint8(i) == line)
+	}
+	s.flushBanks()
+
+	for slot := range s.slotCount() {
+		if s.slotPattern(slot)&(1<<line) != 0 {
+			s.setDigitPin(s.slotPin(slot), true)
+		}
+	}
+	s.flushBanks()
+}
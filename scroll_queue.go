@@ -0,0 +1,111 @@
+package sevseg
+
+// ScrollQueue scrolls several messages one after another over the display.
+//
+// New messages are appended to the messages which are still scrolling,
+// separated by a separator, so there is no visible jump between them. Call
+// Step periodically to advance the text by one digit.
+type ScrollQueue struct {
+	display   *SevSeg
+	separator []uint8
+
+	// stream holds the patterns of all queued messages, position is the index
+	// of the pattern shown on the leftmost digit. Positions outside of the
+	// stream are displayed blank.
+	stream   []uint8
+	position int
+}
+
+// NewScrollQueue creates a new scroll queue for the display. The separator is
+// inserted between two consecutive messages, e.g. " - ".
+func NewScrollQueue(display *SevSeg, separator string) (*ScrollQueue, error) {
+	if display == nil {
+		return nil, ErrInvalidArgument
+	}
+
+	separatorPatterns, err := display.format.Patterns(separator)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &ScrollQueue{
+		display:   display,
+		separator: separatorPatterns,
+	}
+
+	return q, nil
+}
+
+// Push appends a message to the queue.
+//
+// If the queue is idle, the message scrolls in from the right. Otherwise it
+// follows the messages still in the queue, separated by the separator.
+func (q *ScrollQueue) Push(text string) error {
+	patterns, err := q.display.format.Patterns(text)
+	if err != nil {
+		return err
+	}
+
+	if q.Idle() {
+		q.stream = q.stream[:0]
+		q.position = -len(q.display.digitPins)
+	} else {
+		// Drop the patterns which have already been scrolled out
+		if q.position > 0 {
+			q.stream = append(q.stream[:0], q.stream[q.position:]...)
+			q.position = 0
+		}
+
+		q.stream = append(q.stream, q.separator...)
+	}
+
+	q.stream = append(q.stream, patterns...)
+
+	q.render()
+
+	return nil
+}
+
+// Step scrolls the queued messages to the left by one digit/segment.
+//
+// Returns false if all messages have been scrolled out completely.
+func (q *ScrollQueue) Step() bool {
+	if q.Idle() {
+		return false
+	}
+
+	q.position++
+	q.render()
+
+	return !q.Idle()
+}
+
+// Idle reports whether all queued messages have been scrolled out.
+func (q *ScrollQueue) Idle() bool {
+	return q.position >= len(q.stream)
+}
+
+// render writes the currently visible part of the stream to the display.
+func (q *ScrollQueue) render() {
+	q.display.lock()
+	defer q.display.unlock()
+
+	displayWidth := len(q.display.digitPins)
+	blankPattern := q.display.getSegmentCode(36) // BLANK
+
+	for i := 0; i < displayWidth; i++ {
+		pattern := blankPattern
+		if index := q.position + i; index >= 0 && index < len(q.stream) {
+			pattern = q.stream[index]
+		}
+
+		q.display.updatedDisplay[displayWidth-1-i] = pattern
+	}
+
+	// The queue replaces the text of SetText, which must not be scrolled by
+	// AutoScroll or ScrollTextLeft anymore
+	q.display.textPattern = nil
+	q.display.textString = ""
+
+	q.display.commit(ContentText)
+}
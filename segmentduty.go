@@ -0,0 +1,19 @@
+package sevseg
+
+import "math/bits"
+
+// segmentDutyCycle scales a duty cycle, in percent or PWM steps, by the share
+// of lit segment lines of the slot being refreshed, see
+// Config.SegmentCompensation. A slot with any segment lit keeps at least 1,
+// so a dim digit isn't blanked. The duty cycle is returned unchanged without
+// the compensation or while SetDigitOnTime is in use.
+func (s *SevSeg) segmentDutyCycle(duty uint8) uint8 {
+	if !s.segmentDuty || s.scanSegments || s.onTime > 0 || s.currentDigitToRefresh >= s.slotCount() {
+		return duty
+	}
+
+	lit := uint16(bits.OnesCount8(s.slotPattern(s.currentDigitToRefresh)))
+	segments := uint16(s.segmentCount())
+
+	return uint8((uint16(duty)*lit + segments - 1) / segments)
+}
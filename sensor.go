@@ -0,0 +1,72 @@
+package sevseg
+
+// TemperatureSensor is a sensor reporting the temperature in milli °C, like
+// the BME280 and BMP280 drivers of tinygo.org/x/drivers. Other sensors are
+// adapted with TemperatureSensorFunc.
+type TemperatureSensor interface {
+	ReadTemperature() (int32, error)
+}
+
+// TemperatureSensorFunc is an adapter to use an ordinary function as a
+// TemperatureSensor, e.g. for the DS18B20 driver, which reads a sensor on the
+// bus by its ROM ID:
+//
+//	sensor := sevseg.TemperatureSensorFunc(func() (int32, error) {
+//		return ds.ReadTemperature(romID)
+//	})
+type TemperatureSensorFunc func() (int32, error)
+
+// ReadTemperature calls f().
+func (f TemperatureSensorFunc) ReadTemperature() (int32, error) {
+	return f()
+}
+
+// ShowTemperatureFrom reads the sensor and shows the temperature like
+// SetTemperatureRaw, e.g. in the main loop:
+//
+//	display.ShowTemperatureFrom(sensor, sevseg.TemperatureUnit.Celsius)
+//
+// If the reading fails, StatusError ("Err") is shown instead and the error of
+// the sensor is returned. Note that three digits are required.
+func (s *SevSeg) ShowTemperatureFrom(sensor TemperatureSensor, unit tempUnit) error {
+	s.lock()
+	defer s.unlock()
+
+	if sensor == nil {
+		return ErrInvalidArgument
+	}
+
+	if len(s.digitPins) <= 2 {
+		return ErrTooManyDigits // We need at least 3 digits to display a number
+	}
+
+	milli, err := sensor.ReadTemperature()
+	if err != nil {
+		s.ShowStatus(StatusError)
+		return err
+	}
+
+	return s.setTemperatureTenths(milliToTenths(milli, unit == TemperatureUnit.Fahrenheit), unit)
+}
+
+// milliToTenths converts a temperature in milli °C to tenths of a degree in
+// °C or °F, rounded half away from zero.
+func milliToTenths(milli int32, fahrenheit bool) int32 {
+	factor := int64(10)
+	if fahrenheit {
+		factor = 18 // 10 * 9/5
+	}
+
+	scaled := int64(milli) * factor
+	half := int64(500)
+	if scaled < 0 {
+		half = -half
+	}
+
+	tenths := int32((scaled + half) / 1000)
+	if fahrenheit {
+		tenths += 320
+	}
+
+	return tenths
+}
@@ -1,10 +1,9 @@
-//go:build tinygo
-
 // Package sevseg is a library for controlling 7-segment displays.
 package sevseg
 
 import (
-	"machine"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,6 +18,36 @@ var TemperatureUnit = struct {
 	Fahrenheit: 'F',
 }
 
+type trend uint8
+
+// Trend defines the direction in which a displayed temperature is moving. The
+// values are the segment patterns used to indicate the trend.
+var Trend = struct {
+	Stable  trend
+	Rising  trend
+	Falling trend
+}{
+	Stable:  0b01000000, // Segment G
+	Rising:  0b00000001, // Segment A
+	Falling: 0b00001000, // Segment D
+}
+
+type annotation uint8
+
+// Annotation defines the bars used by SetAnnotation to mark a digit. The values
+// are the segment patterns of the bars.
+var Annotation = struct {
+	None      annotation
+	TopBar    annotation
+	BottomBar annotation
+	Both      annotation
+}{
+	None:      0b00000000,
+	TopBar:    0b00000001, // Segment A
+	BottomBar: 0b00001000, // Segment D
+	Both:      0b00001001, // Segments A and D
+}
+
 type pwmType uint8
 
 // HardwarePWM and SoftwarePWM define the type of PWM used for brightness
@@ -28,10 +57,74 @@ const (
 	HardwarePWM
 )
 
-// type pwmChannelMap struct {
-// 	pwm     machine.PWM
-// 	channel uint8
-// }
+// hardwarePWMPeriod is the period in nanoseconds of the hardware PWM, i.e.
+// 20kHz. It's well above the refresh rate, so every digit is lit for many PWM
+// cycles.
+const hardwarePWMPeriod = 50_000
+
+// defaultPWMSteps is the number of steps of the software PWM if
+// Config.PWMSteps isn't set.
+const defaultPWMSteps = 10
+
+// PWM is a PWM peripheral (timer) of the board, e.g. machine.Timer1 on an
+// Arduino Nano or machine.PWM4 on a Raspberry Pi Pico. The TinyGo machine
+// package has a different type for each board, all of which implement this
+// interface.
+type PWM interface {
+	Configure(config machinePWMConfig) error
+	Channel(pin machinePin) (uint8, error)
+	SetInverting(channel uint8, inverting bool)
+	Top() uint32
+	Set(channel uint8, value uint32)
+}
+
+type pwmChannelMap struct {
+	pwm     PWM
+	channel uint8
+}
+
+type contentType uint8
+
+// ContentNone, ContentNumber, ContentFloat, ContentHex, ContentText,
+// ContentSegment and ContentAnimation define the type of content currently
+// shown on the display.
+const (
+	ContentNone contentType = iota
+	ContentNumber
+	ContentFloat
+	ContentHex
+	ContentText
+	ContentSegment
+	ContentAnimation
+)
+
+type shiftRegisterType uint8
+
+// NoShiftRegister, ShiftRegister74HC164 and ShiftRegister74HC595 define how
+// the segment lines are driven. NoShiftRegister uses one pin per segment
+// (SegmentPins), the 74HC164 drives all 8 segment lines from a data and a
+// clock pin. The 74HC595 additionally has a latch, its outputs only change
+// when the shifted pattern is latched.
+const (
+	NoShiftRegister shiftRegisterType = iota
+	ShiftRegister74HC164
+	ShiftRegister74HC595
+)
+
+type signPlacement uint8
+
+// SignAuto, SignLeftmost and SignAdjacent define where the minus sign of a
+// negative number goes relative to the padding, e.g. for -5 on 4 digits:
+//
+//	                leading zeros   no leading zeros
+//	SignAuto        "-005"          "  -5"
+//	SignLeftmost    "-005"          "-  5"
+//	SignAdjacent    "00-5"          "  -5"
+const (
+	SignAuto signPlacement = iota
+	SignLeftmost
+	SignAdjacent
+)
 
 type displayType uint8
 
@@ -49,84 +142,678 @@ type Config struct {
 
 	// PWM defines the type of PWM used for brightness control.
 	//
-	// If you want to use the hardware PWM you need to configure PWMTimers and
-	// PWMPins.
+	// If you want to use the hardware PWM you need to configure PWMPins.
 	PWMType pwmType
 
-	// PWMPins defines the PWM pins e.g., [machine.Timer0, machine.Timer1] or
-	// [machine.PWM3, machine.PWM4] depending on the board.
-	// PWMPins []machine.PWM
+	// PWMPins defines the PWM timers driving the digit pins with HardwarePWM,
+	// e.g. [machine.Timer1, machine.Timer2] or [machine.PWM3, machine.PWM4]
+	// depending on the board. Every digit pin (and aux digit pin) must be an
+	// output of one of them; the brightness is set as the duty cycle of the
+	// lit digit.
+	PWMPins []PWM
+
+	// PWMSteps defines the resolution of the SoftwarePWM, i.e. the number of
+	// Refresh calls per PWM period, e.g. 32 or 64. More steps make more
+	// brightness levels distinguishable, but need a higher refresh rate to
+	// avoid flicker. A value of 0 selects the default of 10 steps.
+	PWMSteps uint8
 
 	// DigitPins defines the pins used control/multiplex the digits.
-	DigitPins []machine.Pin
+	DigitPins []OutputPin
+
+	// ReverseDigitOrder defines whether the order of the DigitPins should be
+	// reversed. This is useful for boards which label the digits from right
+	// to left relative to the connector.
+	ReverseDigitOrder bool
 
 	// SegmentPins defines the pins used to control the segments of the display.
 	// Normally, these are 7 or 8 pins, depending on whether a decimal point is
 	// used.
-	SegmentPins []machine.Pin
+	SegmentPins []OutputPin
+
+	// GreenSegmentPins and BlueSegmentPins define the segment buses of
+	// tri-color (or bi-color) digits, which share the DigitPins with the
+	// SegmentPins driving the red segments. Each bus needs as many pins as
+	// SegmentPins, in the same order. Use SetColor to choose the color of a
+	// digit. Not supported with a shift register or a controller.
+	GreenSegmentPins []OutputPin
+	BlueSegmentPins  []OutputPin
+
+	// Decoder defines whether the SegmentPins drive a BCD-to-7-segment
+	// decoder, which only needs 4 data lines (5 with a decimal point).
+	Decoder decoderType
+
+	// ShiftRegister defines whether the segment lines are driven by a shift
+	// register instead of the SegmentPins. The 74HC164 has no latch, so its
+	// outputs follow every clock pulse; the digits are blanked while shifting
+	// to prevent smearing. Its outputs Q0-Q7 are wired to the segments A-G
+	// and DP, SegmentPins is ignored.
+	ShiftRegister shiftRegisterType
+
+	// ShiftDataPin and ShiftClockPin define the pins connected to the data
+	// (A and B tied together, or SER) and clock (SRCLK) inputs of the shift
+	// register.
+	ShiftDataPin  OutputPin
+	ShiftClockPin OutputPin
+
+	// ShiftLatchPin defines the pin connected to the latch input (RCLK) of a
+	// 74HC595.
+	ShiftLatchPin OutputPin
+
+	// ShiftSPI defines an SPI bus which shifts the pattern out in hardware
+	// instead of ShiftDataPin and ShiftClockPin, e.g. machine.SPI0 with SDO
+	// wired to the data and SCK to the clock input. The bus must be
+	// configured before (mode 0, MSB first).
+	ShiftSPI SPI
+
+	// Controller defines whether the display is driven by a controller chip
+	// which multiplexes the digits itself, e.g. a TM1637. The content is then
+	// sent to the controller as soon as it changes, and Refresh is only
+	// needed for effects like AlternateWith. DigitPins, SegmentPins and the
+	// PWM and shift register settings are ignored, aux digits aren't
+	// supported.
+	Controller controllerType
+
+	// ControllerClockPin and ControllerDataPin define the pins connected to
+	// the clock (CLK) and data (DIO) lines of the controller.
+	ControllerClockPin machinePin
+	ControllerDataPin  machinePin
+
+	// ControllerSPI and ControllerLoadPin define the SPI bus and the LOAD (or
+	// CS) pin of an SPI controller like the MAX7219. The bus must be
+	// configured before, e.g. with machine.SPI0.Configure. The TM1638 uses
+	// ControllerLoadPin as strobe (STB) pin in addition to ControllerClockPin
+	// and ControllerDataPin.
+	ControllerSPI     SPI
+	ControllerLoadPin machinePin
+
+	// ControllerI2C and ControllerAddress define the I2C bus and the address
+	// of an I2C controller like the HT16K33. The bus must be configured
+	// before, e.g. with machine.I2C0.Configure. An address of 0 selects the
+	// default address of the controller, 0x70 for the HT16K33.
+	ControllerI2C     I2C
+	ControllerAddress uint16
+
+	// ControllerDigits defines the number of digits driven by the controller,
+	// at most 6 for the TM1637, 8 for the MAX7219 and TM1638 and 7 for the
+	// HT16K33. A multiple of 8 up to 64 drives a chain of MAX7219 modules,
+	// see SetTextOnModule.
+	ControllerDigits uint8
 
 	// UseLeadingZeros defines whether leading zeros should be displayed.
 	UseLeadingZeros bool
+
+	// SignPlacement defines where the minus sign goes relative to the
+	// padding. By default (SignAuto) it's on the left most digit when padding
+	// with zeros and directly left of the number otherwise.
+	SignPlacement signPlacement
+
+	// PairZeroPadding defines which halves of SetPair are padded with zeros
+	// instead of blanks, e.g. PairPadRight for a clock-style "12-03".
+	PairZeroPadding pairPadding
+
+	// DutyReferenceDigits enables the brightness compensation for the number
+	// of multiplexed digits. Each digit is only lit 1/n of the time, so the
+	// same brightness looks very different on displays with 2 and 8 digits.
+	// When set, the brightness is scaled to match a display with the given
+	// number of digits, e.g. 8. A value of 0 disables the compensation.
+	DutyReferenceDigits uint8
+
+	// SegmentCompensation scales the duty cycle of each digit by its number
+	// of lit segments. Without segment resistors, the segments of a digit
+	// share the current, so a "1" looks much brighter than an "8". With the
+	// compensation, a digit is lit for the share of its lit segments, which
+	// makes all digits equally bright at the cost of the overall brightness.
+	// Displays with a Controller ignore it.
+	SegmentCompensation bool
+
+	// DitherBrightness enables temporal dithering of the software PWM. The
+	// brightness is otherwise rounded up to the PWMSteps duty levels of the
+	// PWM, so with 10 steps 1-10% all look the same. With dithering,
+	// consecutive PWM periods alternate between the adjacent levels to match
+	// the brightness on average, e.g. for dark-room clocks. Very low values
+	// may flicker if Refresh isn't called often enough.
+	DitherBrightness bool
+
+	// LinearBrightness disables the gamma correction of the brightness. The
+	// eye perceives LED brightness non-linearly, so 50% duty looks almost as
+	// bright as 100%. By default, the brightness is therefore mapped to the
+	// duty cycle with a gamma of 2, e.g. 50% to 25% duty, so the scale of
+	// SetBrightness feels linear. When set, the brightness is the duty cycle.
+	LinearBrightness bool
+
+	// AvoidDeadSegments defines whether glyphs using a segment declared dead
+	// with MarkSegmentDead are replaced by an alternate with the same meaning
+	// where one exists, e.g. a lowercase 'o' for '0'. This keeps displays with
+	// a burnt-out segment usable.
+	AvoidDeadSegments bool
+
+	// TrimTrailingZeros defines whether trailing zeros after the decimal point
+	// should be removed by SetNumberFloat (e.g. 1.50 -> 1.5). The freed digits
+	// can hold a larger integer part.
+	TrimTrailingZeros bool
+
+	// MaskDecimalPoint defines whether SetSegment and PlayFrames drop the
+	// decimal point of patterns on displays without a decimal point pin,
+	// instead of returning ErrNoDecimalPointPin. By default such patterns are
+	// rejected, so a wiring or pattern mismatch surfaces early.
+	MaskDecimalPoint bool
+
+	// ExpandWideLetters defines whether M and W, which can't be shown on a
+	// single digit, are expanded to two-digit approximations in text instead
+	// of being blank. This keeps words readable in scrolling text.
+	ExpandWideLetters bool
+
+	// FontName selects a font registered with RegisterFont. An empty name
+	// selects the default font, see SetDefaultFont.
+	FontName string
+
+	// HistoryDepth defines the number of committed frames kept for
+	// GetHistory, e.g. for field debugging of intermittent issues. Each frame
+	// takes one byte per digit. A value of 0 disables the history.
+	HistoryDepth uint8
+
+	// DigitDeadTime defines the time in microseconds between turning off a
+	// digit and driving the segments of the next one. On fast MCUs the
+	// previous digit line may not be fully off yet, e.g. due to the slow
+	// turn-off of a driver transistor, so the next pattern shows faintly on
+	// it (ghosting). The dead time is a busy wait in every Refresh call,
+	// which reduces the maximum duty cycle. A value of 0 disables it.
+	// Displays with a Controller ignore it.
+	DigitDeadTime uint16
+
+	// ScanMode defines whether Refresh lights one digit (ScanDigits) or one
+	// segment line across all digits (ScanSegments) per step. ScanSegments
+	// requires the SegmentPins to drive the segment lines directly, without a
+	// Controller, a shift register, a decoder or tri-color digits, and
+	// ignores SegmentCompensation.
+	ScanMode scanMode
+
+	// ButtonCommonPin defines the common line of buttons wired to the segment
+	// lines, which ScanButtons drives low while reading them. It's an input
+	// (high impedance) otherwise, so pressed buttons don't disturb the
+	// display. Without it, the buttons are expected to pull their line to
+	// ground through a resistor.
+	ButtonCommonPin OutputPin
+
+	// RefreshEngine defines whether the digits are multiplexed by Refresh
+	// (RefreshCPU) or by a PIO state machine of the RP2040 or RP2350
+	// (RefreshPIO). The PIO engine drives up to 8 digits and 7 or 8 segments
+	// on plain GPIOs below 30, without a shift register, a decoder,
+	// HardwarePWM, aux digits or indicator pins, and requires the rp2040 or
+	// rp2350 target.
+	RefreshEngine refreshEngine
+
+	// RefreshTimer defines a hardware timer driving StartAutoRefresh from its
+	// interrupt. Without it, StartAutoRefresh uses a goroutine.
+	RefreshTimer RefreshTimer
+
+	// AuxDigitPins defines additional common pins which are multiplexed like
+	// digits but drive discrete LEDs (e.g. signal-strength bars) wired to the
+	// segment lines. Each pin provides one LED per segment pin, addressable
+	// with SetAuxLED.
+	AuxDigitPins []OutputPin
+
+	// ClockIndicatorPin defines the common pin of the colon (L1, L2) and
+	// apostrophe (L3) LEDs found on 4-digit clock modules. It's multiplexed
+	// like a digit and only used if ColonSegments or ApostropheSegments is
+	// set.
+	ClockIndicatorPin OutputPin
+
+	// ColonSegments defines the segment lines lighting the colon LEDs (L1,
+	// L2) on the ClockIndicatorPin, e.g. 0b00000011 for segments A and B.
+	ColonSegments uint8
+
+	// ApostropheSegments defines the segment lines lighting the apostrophe
+	// LED (L3) on the ClockIndicatorPin, e.g. 0b00000100 for segment C.
+	ApostropheSegments uint8
+
+	// ColonPin and ApostrophePin define pins driving the colon (L1, L2) and
+	// apostrophe (L3) LEDs of clock modules directly, as an alternative to
+	// the ClockIndicatorPin, e.g. on modules with separate LED pins. They're
+	// active like the segment pins and lit in the multiplex step of the right
+	// most digit, so the LEDs are about as bright as the digits. A ColonPin
+	// can't be combined with ColonSegments, an ApostrophePin not with
+	// ApostropheSegments.
+	ColonPin      OutputPin
+	ApostrophePin OutputPin
+
+	// SignDigitPin defines the common pin of a dedicated sign element left of
+	// the digits, found on some instrument displays. It's multiplexed like a
+	// digit and only used if SignMinusSegments is set. Negative numbers then
+	// light the minus on the sign element instead of using a full digit.
+	SignDigitPin OutputPin
+
+	// SignMinusSegments defines the segment lines lighting the minus of the
+	// sign element on the SignDigitPin, e.g. 0b01000000 for segment G.
+	SignMinusSegments uint8
 }
 
 // SevSeg represents a 7-segment display.
 type SevSeg struct {
-	config          displayType
-	pwm             pwmType
-	digitPins       []machine.Pin
-	segmentPins     []machine.Pin
-	useLeadingZeros bool
-
-	// Internal state
-	enabled    bool
-	brightness uint8
-	// pwmChannels map[machine.Pin]pwmChannelMap
+	config         displayType
+	pwm            pwmType
+	digitPins      []OutputPin
+	segmentPins    []OutputPin
+	shiftRegister  shiftRegisterType
+	shiftDataPin   OutputPin
+	shiftClockPin  OutputPin
+	shiftLatchPin  OutputPin
+	shiftSPI       SPI
+	shiftBuf       [1]byte
+	decoder        decoderType
+	segmentPort    segmentPort
+	useSegmentPort bool      // Whether all segment pins are on the same port
+	banks          []PinBank // Banks of the pins, flushed after each step
+
+	// Tri-color state, colors is nil without GreenSegmentPins and
+	// BlueSegmentPins
+	colorBusPins    [colorBuses][]OutputPin // The SegmentPins followed by the green and blue buses
+	colors          []color
+	currentColorBus uint8 // Bus lit in the current slot, colorBuses if none
+	auxDigitPins    []OutputPin
+	auxLEDGroups    uint8
+	pairPadding     pairPadding
+	trimZeros       bool
+	maskDP          bool // Whether patterns with a DP bit are masked instead of rejected
+	dutyReference   uint8
+	onTime          uint16 // Microseconds each digit is lit with SetDigitOnTime, 0 if unused
+	dither          bool
+	linearDuty      bool // Whether the brightness is the duty cycle, without gamma correction
+	avoidDead       bool
+	segmentDuty     bool   // Whether the duty cycle is scaled by the lit segments
+	scanSegments    bool   // Whether Refresh lights a segment line per step instead of a digit
+	deadTime        uint16 // Microseconds between turning off a digit and driving the next
+	buttonPin       OutputPin
+	shareWindow     func() // Called between frames with the segment pins released, nil if unused
+	windowDue       bool   // Whether the next Refresh opens the shared pin window
+
+	// Clock indicator and sign element state, the indicator is the aux digit
+	// after the AuxDigitPins, the sign element the last aux digit
+	colonSegments      uint8
+	apostropheSegments uint8
+	colonPin           OutputPin
+	apostrophePin      OutputPin
+	colonLit           bool // Whether the LED on the ColonPin is lit
+	apostropheLit      bool // Whether the LED on the ApostrophePin is lit
+	separatorColon     bool // Whether the colon is lit to separate the fields of the content
+	signSegments       uint8
+
+	// Internal state, enabled is changed by On and Off, visible by Toggle
+	enabled     bool
+	visible     bool
+	brightness  uint8
+	pwmChannels map[OutputPin]pwmChannelMap // Channels of the slot pins with HardwarePWM
+
+	// Type of the content currently shown
+	contentType contentType
 
 	// Text scrolling state
 	scrollPosition int
 	textPattern    []uint8
+	textString     string        // Patterns of SetTextPatterns, read in place
+	scrollPeriod   uint16        // Ticks per step of AutoScroll, 0 if disabled
+	scrollStep     time.Duration // Time per step of AutoScrollEvery, 0 if disabled
+	scrollEasing   scrollEasing
+	scrollNext     uint32
+	scrollDue      time.Time // Time of the next step of AutoScrollEvery
+
+	// Alternating content state
+	alternateContent []uint8
+	alternatePeriod  uint16
+	alternateNext    uint32
+	showAlternate    bool
+
+	// Warning state
+	warningContent []uint8
+	warningPeriod  uint16
+	warningNext    uint32
+	showWarning    bool
+
+	// Ring buffer of the last committed frames
+	history      []uint8
+	historyNext  uint8
+	historyCount uint8
+
+	// Frame playback state
+	playFrames [][]uint8
+	playString string // Frames of PlayFrameString, read in place
+	playWidth  int
+	playPeriod uint16
+	playNext   uint32
+	playIndex  int
+
+	// Character map state, charMapPeriod is 0 unless ShowCharacterMap runs
+	charMapPeriod uint16
+	charMapNext   uint32
+	charMapIndex  uint8
+
+	// Clock face state, clockFace is set while SetClock's content is shown
+	clockFace        bool
+	clockBlinkPeriod uint16
+	clockBlinkNext   uint32
+	clockBlinkOff    bool // Whether the separator is hidden by the blinking
+
+	// Burn-in rotation state, burnInPeriod is 0 unless SetBurnInShift is used
+	burnInPeriod uint16
+	burnInNext   uint32
+	burnInOffset uint8 // Digits the content is rotated to the left
+
+	// Crossfade state, fadeFrom holds the previous content while a transition
+	// runs. Both buffers are allocated by SetCrossfade, as the transitions
+	// run from Refresh, which may be called from an interrupt.
+	fadeFrames  uint16
+	fadeFrom    []uint8
+	fadeActive  bool
+	fadeStep    uint16
+	fadeError   uint16 // Accumulates the share of frames showing the new content
+	fadeShowNew bool
+	fadeLevels  []uint8 // Levels sent to a SegmentLevelDriver
+
+	// Formatting of the content, including the aliases and numerals
+	format Formatter
+
+	// Batch state, batchFrame holds the frame shown until End (allocated on
+	// first use)
+	batchDepth   uint8
+	batchFrame   []uint8
+	batchChanged bool
+
+	// Segments declared dead, one mask per digit (allocated on first use)
+	deadSegments []uint8
+
+	// Bars OR-composed over the content, one per digit (allocated on first use)
+	annotations []uint8
+
+	// Digits showing a multi-frame glyph
+	animations []animatedDigit
+
+	// Lease of the component currently owning the display
+	lease *Lease
+
+	// Driver receiving every committed frame
+	mirror Driver
+
+	// Orientation state
+	orientationProvider func() Orientation
+	upsideDown          bool
+
+	// Idle timeout state
+	idleTimeout    uint32
+	idleSince      uint32 // Clock of the last content update
+	idleBrightness uint8
+
+	// Change highlight state
+	lastNumber         int32
+	lastNumberSet      bool
+	highlightThreshold uint32
+	highlightBlinks    uint8
+	highlightPeriod    uint16
+	highlightPhases    uint16 // Remaining on and off phases, even ones are off
+	highlightNext      uint32
+
+	// Refresh throttling state
+	throttleInterval time.Duration
+	lastRefresh      time.Time
+	dirty            bool
+	lit              bool // Result of the last performed Refresh
+
+	// Refresh rate measurement, see GetFrameStats
+	statsEnabled bool
+	statsStart   time.Time
+	statsLast    time.Time
+	statsCalls   uint32
+	statsFrames  uint32
+	statsMin     time.Duration
+	statsMax     time.Duration
+	frameStats   FrameStats
+
+	// Auto refresh state, the stop and done channels are nil unless the
+	// goroutine is running
+	refreshTimer    RefreshTimer
+	timerRunning    bool
+	pendingTicks    atomic.Uint32 // Clock ticks counted in the timer interrupt
+	autoRefreshStop chan struct{}
+	autoRefreshDone chan struct{}
+
+	// The API and the auto refresh goroutine hold mu while they use the
+	// state. lockDepth counts the nested API calls holding it, it's only used
+	// by the goroutine calling the API.
+	mu        sync.Mutex
+	lockDepth uint8
+
+	// Controller state, backendFrame holds the content sent to the backend
+	backend      backend
+	pioRefresh   bool // Whether the backend is the PIO refresh engine
+	backendFrame []uint8
 
 	// Refresh state
+	pwmSteps              uint8
 	pwmCounter            uint8
+	pwmLevel              uint8 // Lit steps of the current PWM period when dithering
+	ditherError           uint8
 	currentDigitToRefresh uint8
-	updatedDisplay        []uint8
+	clock                 uint32  // Effects clock, ticks once per full frame
+	frameStart            bool    // Whether the scan is at the start of a frame
+	updatedDisplay        []uint8 // Back buffer written by the setters
+	stagedDisplay         []uint8 // Last committed content, see stageFrame
+	liveDisplay           []uint8 // Content shown by Refresh
+	staged                atomic.Bool
+	auxDisplay            []uint8
 }
 
 // NewSevSeg creates a new instance of sevSeg with the provided configuration.
-func NewSevSeg(cfg Config) (*SevSeg, bool) {
-	if len(cfg.DigitPins) == 0 || len(cfg.SegmentPins) < 7 || len(cfg.SegmentPins) > 8 {
-		return nil, false
+//
+// Returns ErrInvalidConfig if there are no digit pins, fewer than 7 or more
+// than 8 segment pins or a required pin is nil. With a Controller,
+// ControllerDigits must be set and no aux digits may be configured.
+// ScanSegments requires segment pins without a shift register, a decoder or
+// tri-color digits. Returns ErrUnknownFont if the FontName hasn't been
+// registered.
+func NewSevSeg(cfg Config) (*SevSeg, error) {
+	if _, ok := lookupFont(cfg.FontName); !ok {
+		return nil, ErrUnknownFont
+	}
+
+	if cfg.Controller != NoController {
+		return newControlledSevSeg(cfg)
+	}
+
+	if cfg.RefreshEngine == RefreshPIO {
+		return newPIOSevSeg(cfg)
+	}
+
+	if len(cfg.DigitPins) == 0 {
+		return nil, ErrInvalidConfig
+	}
+
+	if cfg.Decoder != NoDecoder {
+		// The aux digits, the clock indicator and the sign element show
+		// patterns which aren't digits
+		if cfg.ShiftRegister != NoShiftRegister || len(cfg.SegmentPins) < 4 || len(cfg.SegmentPins) > 5 ||
+			len(cfg.GreenSegmentPins) > 0 || len(cfg.BlueSegmentPins) > 0 || len(cfg.AuxDigitPins) > 0 ||
+			cfg.ColonSegments|cfg.ApostropheSegments|cfg.SignMinusSegments != 0 {
+			return nil, ErrInvalidConfig
+		}
+	} else if cfg.ShiftRegister == NoShiftRegister && (len(cfg.SegmentPins) < 7 || len(cfg.SegmentPins) > 8) {
+		return nil, ErrInvalidConfig
+	}
+
+	if missingPins(cfg) || bankedShiftPins(cfg) || !validColorBuses(cfg) {
+		return nil, ErrInvalidConfig
+	}
+
+	// An indicator is either on the ClockIndicatorPin or on a pin of its own
+	if cfg.ColonPin != nil && cfg.ColonSegments != 0 || cfg.ApostrophePin != nil && cfg.ApostropheSegments != 0 {
+		return nil, ErrInvalidConfig
+	}
+
+	// Scanning segments drives each segment line on its own
+	if cfg.ScanMode == ScanSegments && (cfg.ShiftRegister != NoShiftRegister || cfg.Decoder != NoDecoder ||
+		len(cfg.GreenSegmentPins) > 0 || len(cfg.BlueSegmentPins) > 0) {
+		return nil, ErrInvalidConfig
+	}
+
+	s := newSevSeg(cfg, cfg.DigitPins)
+	s.banks = appendBanks(s.banks, s.digitPins)
+	s.banks = appendBanks(s.banks, s.auxDigitPins)
+	s.banks = appendBanks(s.banks, s.segmentPins)
+	s.banks = appendBanks(s.banks, cfg.GreenSegmentPins)
+	s.banks = appendBanks(s.banks, cfg.BlueSegmentPins)
+	s.banks = appendBanks(s.banks, []OutputPin{cfg.ColonPin, cfg.ApostrophePin})
+
+	// Drive every pin to its off state right after configuring it. The level
+	// of a freshly configured output depends on the MCU, so without this the
+	// first frame would depend on the display type.
+	for i := range s.slotCount() {
+		s.slotPin(i).Configure(PinConfig{Mode: machinePinOutput})
+		s.setDigitPin(s.slotPin(i), false)
+	}
+
+	for _, pin := range []OutputPin{s.colonPin, s.apostrophePin} {
+		if pin != nil {
+			pin.Configure(PinConfig{Mode: machinePinOutput})
+		}
+	}
+	s.clearIndicatorPins()
+
+	if s.shiftRegister != NoShiftRegister {
+		if s.shiftSPI == nil {
+			s.shiftDataPin.Configure(PinConfig{Mode: machinePinOutput})
+			s.shiftClockPin.Configure(PinConfig{Mode: machinePinOutput})
+			s.shiftClockPin.Low()
+		}
+
+		if s.shiftRegister == ShiftRegister74HC595 {
+			s.shiftLatchPin.Configure(PinConfig{Mode: machinePinOutput})
+			s.shiftLatchPin.Low()
+		}
+
+		s.clearSegmentPins()
+	} else {
+		for _, pins := range s.colorBusPins {
+			for _, pin := range pins {
+				pin.Configure(PinConfig{Mode: machinePinOutput})
+				s.setSegmentPin(pin, false)
+			}
+		}
+
+		if s.decoder != NoDecoder {
+			s.clearSegmentPins()
+		} else {
+			s.segmentPort, s.useSegmentPort = newSegmentPort(s.segmentPins)
+		}
+	}
+
+	if s.buttonPin != nil {
+		s.buttonPin.Configure(PinConfig{Mode: machinePinInput})
 	}
 
-	for _, pin := range cfg.DigitPins {
-		pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	s.flushBanks()
+
+	if s.pwm == HardwarePWM && !s.configurePWM(cfg.PWMPins) {
+		return nil, ErrInvalidConfig
+	}
+
+	// Refresh may be called before any content is set, it shows a blank frame.
+	s.Clear()
+
+	return s, nil
+}
+
+// newSevSeg creates the display state for the given configuration and digit
+// pins, without touching any pins.
+func newSevSeg(cfg Config, digitPins []OutputPin) *SevSeg {
+	if cfg.ReverseDigitOrder {
+		reversed := make([]OutputPin, len(digitPins))
+		for i, pin := range digitPins {
+			reversed[len(reversed)-1-i] = pin
+		}
+		digitPins = reversed
 	}
 
-	for _, pin := range cfg.SegmentPins {
-		pin.Configure(machine.PinConfig{Mode: machine.PinOutput})
+	auxDigitPins := cfg.AuxDigitPins
+	if cfg.ColonSegments|cfg.ApostropheSegments != 0 {
+		auxDigitPins = append(auxDigitPins[:len(auxDigitPins):len(auxDigitPins)], cfg.ClockIndicatorPin)
+	}
+
+	if cfg.SignMinusSegments != 0 {
+		auxDigitPins = append(auxDigitPins[:len(auxDigitPins):len(auxDigitPins)], cfg.SignDigitPin)
 	}
 
 	s := &SevSeg{
-		config:          cfg.Hardware,
-		pwm:             cfg.PWMType,
-		digitPins:       cfg.DigitPins,
-		segmentPins:     cfg.SegmentPins,
-		useLeadingZeros: cfg.UseLeadingZeros,
-		brightness:      100,
-		enabled:         true,
-		// pwmChannels:           make(map[machine.Pin]pwmChannelMap),
-		updatedDisplay:        make([]uint8, len(cfg.DigitPins)),
+		config:                cfg.Hardware,
+		pwm:                   cfg.PWMType,
+		pwmSteps:              cfg.PWMSteps,
+		digitPins:             digitPins,
+		segmentPins:           cfg.SegmentPins,
+		shiftRegister:         cfg.ShiftRegister,
+		shiftDataPin:          cfg.ShiftDataPin,
+		shiftClockPin:         cfg.ShiftClockPin,
+		shiftLatchPin:         cfg.ShiftLatchPin,
+		shiftSPI:              cfg.ShiftSPI,
+		decoder:               cfg.Decoder,
+		auxDigitPins:          auxDigitPins,
+		auxLEDGroups:          uint8(len(cfg.AuxDigitPins)),
+		trimZeros:             cfg.TrimTrailingZeros,
+		maskDP:                cfg.MaskDecimalPoint,
+		dutyReference:         cfg.DutyReferenceDigits,
+		dither:                cfg.DitherBrightness,
+		linearDuty:            cfg.LinearBrightness,
+		segmentDuty:           cfg.SegmentCompensation,
+		scanSegments:          cfg.ScanMode == ScanSegments,
+		deadTime:              cfg.DigitDeadTime,
+		buttonPin:             cfg.ButtonCommonPin,
+		clockBlinkPeriod:      defaultClockBlink,
+		avoidDead:             cfg.AvoidDeadSegments,
+		refreshTimer:          cfg.RefreshTimer,
+		brightness:            100,
+		enabled:               true,
+		visible:               true,
+		updatedDisplay:        make([]uint8, len(digitPins)),
+		stagedDisplay:         make([]uint8, len(digitPins)),
+		liveDisplay:           make([]uint8, len(digitPins)),
+		auxDisplay:            make([]uint8, len(auxDigitPins)),
+		history:               make([]uint8, int(cfg.HistoryDepth)*len(digitPins)),
+		colonSegments:         cfg.ColonSegments,
+		apostropheSegments:    cfg.ApostropheSegments,
+		colonPin:              cfg.ColonPin,
+		apostrophePin:         cfg.ApostrophePin,
+		signSegments:          cfg.SignMinusSegments,
 		currentDigitToRefresh: 0,
+		frameStart:            true,
 	}
 
-	// if s.pwm == HardwarePWM && !s.configurePWM(cfg.PWMPins) {
-	// 	return nil, false
-	// }
+	if s.pwmSteps == 0 {
+		s.pwmSteps = defaultPWMSteps
+	}
 
-	s.clearDigitPins()
-	s.clearSegmentPins()
+	font, _ := lookupFont(cfg.FontName)
+
+	s.format = Formatter{
+		UseLeadingZeros:   cfg.UseLeadingZeros,
+		SignPlacement:     cfg.SignPlacement,
+		ExpandWideLetters: cfg.ExpandWideLetters,
+		DecimalPoint:      s.segmentCount() == 8,
+		Font:              font,
+		bcdOnly:           cfg.Decoder != NoDecoder,
+	}
+
+	// Negative numbers light the sign element instead of a minus digit
+	s.format.signElement = cfg.SignMinusSegments != 0
 
-	return s, true
+	s.colorBusPins[0] = cfg.SegmentPins
+	if len(cfg.GreenSegmentPins) > 0 || len(cfg.BlueSegmentPins) > 0 {
+		s.colorBusPins[1] = cfg.GreenSegmentPins
+		s.colorBusPins[2] = cfg.BlueSegmentPins
+
+		s.colors = make([]color, len(digitPins))
+		for i := range s.colors {
+			s.colors[i] = Color.Red
+		}
+	}
+
+	return s
 }
 
 // DisplayTest is a standalone method that can be used to test the functionality
@@ -137,6 +824,9 @@ func NewSevSeg(cfg Config) (*SevSeg, bool) {
 //
 // Note that this method must not require to call Refresh externally.
 func (s *SevSeg) DisplayTest(delayMS uint16) {
+	s.lock()
+	defer s.unlock()
+
 	segmentPatterns := []uint8{
 		0b00000001, // Segment A
 		0b00000010, // Segment B
@@ -149,8 +839,9 @@ func (s *SevSeg) DisplayTest(delayMS uint16) {
 	}
 
 	for i := range len(s.digitPins) {
-		for j := range len(s.segmentPins) {
+		for j := range s.segmentCount() {
 			s.updatedDisplay[i] = segmentPatterns[j]
+			s.stageFrame()
 
 			for range delayMS {
 				s.Refresh()
@@ -158,6 +849,7 @@ func (s *SevSeg) DisplayTest(delayMS uint16) {
 			}
 
 			s.updatedDisplay[i] = s.getSegmentCode(36) // BLANK
+			s.stageFrame()
 		}
 	}
 }
@@ -167,103 +859,315 @@ func (s *SevSeg) DisplayTest(delayMS uint16) {
 //
 // Since this library doesn't handle timing, the blinking interval must be
 // handled by the user by passing a toggling boolean value.
+//
+// The blink state is independent of Off and On.
 func (s *SevSeg) Toggle(enable bool) {
-	s.enabled = enable
+	s.lock()
+	defer s.unlock()
+
+	s.visible = enable
+	s.invalidate()
 }
 
 // Clear clears the display by setting all segments to blank.
 func (s *SevSeg) Clear() {
+	s.lock()
+	defer s.unlock()
+
 	for i := range s.updatedDisplay {
 		s.updatedDisplay[i] = s.getSegmentCode(36) // BLANK
 	}
+
+	s.commit(ContentNone)
 }
 
 // Off turns the display off by setting all digit and segment pins to their
 // respective off state, depending on the display type.
 //
-// This turns off the display immediately without calling Refresh.
+// This turns off the display immediately without calling Refresh. The
+// brightness, blink and scroll state as well as effects like AlternateWith are
+// kept and resume with On.
 func (s *SevSeg) Off() {
+	s.lock()
+	defer s.unlock()
+
 	s.enabled = false
+
+	if s.backend != nil {
+		s.pushFrame()
+		return
+	}
+
 	s.clearDigitPins()
 	s.clearSegmentPins()
+	s.flushBanks()
 }
 
-// On turns the display on.
+// On turns the display on again after Off, with the brightness and blink
+// state it had before.
 func (s *SevSeg) On() {
+	s.lock()
+	defer s.unlock()
+
+	s.enabled = true
+	s.invalidate()
+}
+
+// Reset turns the display on with full brightness and stops blinking, i.e. it
+// undoes Off, SetBrightness and Toggle(false) and ends a change highlight.
+func (s *SevSeg) Reset() {
+	s.lock()
+	defer s.unlock()
+
 	s.enabled = true
+	s.visible = true
+	s.brightness = 100
+	s.highlightPhases = 0
+	s.invalidate()
+}
+
+// SetAnnotation marks the digit at position (zero indexed from the right) with
+// a bar over and/or under its content, e.g. to mark a selected field or a
+// limit on instrument displays. The bars are lit in addition to the content
+// and stay when the content changes. Annotation.None removes the annotation.
+func (s *SevSeg) SetAnnotation(position uint8, bars annotation) error {
+	s.lock()
+	defer s.unlock()
+
+	if position >= uint8(len(s.digitPins)) {
+		return ErrOutOfRange
+	}
+
+	if s.annotations == nil {
+		if bars == Annotation.None {
+			return nil
+		}
+
+		s.annotations = make([]uint8, len(s.digitPins))
+	}
+
+	s.annotations[position] = uint8(bars)
+	s.invalidate()
+
+	return nil
+}
+
+// ClearAnnotations removes the annotations of all digits.
+func (s *SevSeg) ClearAnnotations() {
+	s.lock()
+	defer s.unlock()
+
+	s.annotations = nil
+	s.invalidate()
+}
 
-	if s.brightness == 0 {
-		s.brightness = 100
+// InstallPanicBlank turns off all digit and segment pins if the program
+// panics and then continues panicking. Without it, a panic can leave a single
+// digit lit at full current indefinitely. It must be deferred directly, e.g.
+// at the top of main or of the goroutine calling Refresh:
+//
+//	defer display.InstallPanicBlank()
+//
+// This relies on recover, which isn't supported by TinyGo on every target.
+func (s *SevSeg) InstallPanicBlank() {
+	if r := recover(); r != nil {
+		s.Off()
+		panic(r)
 	}
 }
 
+// ContentType returns the type of content currently shown on the display,
+// e.g. so a menu system can decide how to resume or modify it.
+func (s *SevSeg) ContentType() contentType {
+	s.lock()
+	defer s.unlock()
+
+	return s.contentType
+}
+
 // GetDisplayWidth returns the amount of digits the display has.
 func (s *SevSeg) GetDisplayWidth() uint8 {
 	return uint8(len(s.digitPins))
 }
 
+// HasDecimalPoint reports whether the display has a decimal point, i.e. 8
+// segment lines. Without it, decimal numbers and the '.' character are
+// rejected with ErrNoDecimalPointPin, while temperatures are shown without
+// decimal places.
+func (s *SevSeg) HasDecimalPoint() bool {
+	return s.segmentCount() == 8
+}
+
 // IsCharacterSupported checks if a specific character can be displayed.
 func (s *SevSeg) IsCharacterSupported(char byte) bool {
-	_, ok := s.charToSegmentPattern(char)
+	s.lock()
+	defer s.unlock()
+
+	_, ok := s.format.appendGlyph(nil, char)
 	return ok
 }
 
 // SetBrightness sets the brightness of the display.
 //
 // Takes the brightness level in percentage (0-100) as an argument.
-// Any value greater than 100 will be clamped to 100.
+// Any value greater than 100 will be clamped to 100. A brightness of 0 blanks
+// the display, but unlike Off it doesn't turn it off.
 func (s *SevSeg) SetBrightness(brightness uint8) {
-	if brightness == 0 {
-		s.enabled = false
-	} else {
-		s.enabled = true
+	s.lock()
+	defer s.unlock()
+
+	s.brightness = min(brightness, 100)
+	s.invalidate()
+}
+
+// SetBrightnessLevel sets the brightness of the display in steps, e.g. level
+// 3 of 8. Level 0 blanks the display, maxLevels is full brightness. Any
+// level greater than maxLevels will be clamped to maxLevels.
+//
+// The step is mapped to the native brightness control of the display, i.e.
+// the PWM duty cycle for displays driven by GPIO.
+func (s *SevSeg) SetBrightnessLevel(level, maxLevels uint8) error {
+	s.lock()
+	defer s.unlock()
+
+	if maxLevels == 0 {
+		return ErrInvalidArgument
+	}
+
+	level = min(level, maxLevels)
+	s.SetBrightness(uint8(uint16(level) * 100 / uint16(maxLevels)))
+
+	return nil
+}
+
+// SetAuxLED turns an auxiliary LED on or off.
+//
+// The LEDs are numbered continuously over all AuxDigitPins, i.e. index 0 is
+// the LED on segment A of the first aux pin, index 8 (or 7 without DP) is the
+// LED on segment A of the second aux pin.
+func (s *SevSeg) SetAuxLED(index uint8, on bool) error {
+	s.lock()
+	defer s.unlock()
+
+	segmentCount := s.segmentCount()
+
+	group := index / segmentCount
+	if group >= s.auxLEDGroups {
+		return ErrOutOfRange
 	}
 
-	if brightness > 100 {
-		brightness = 100
+	mask := uint8(1) << (index % segmentCount)
+	if on {
+		s.auxDisplay[group] |= mask
 	} else {
-		s.brightness = brightness
+		s.auxDisplay[group] &^= mask
+	}
+
+	s.commit(s.contentType)
+
+	return nil
+}
+
+// SetColon turns the colon (L1, L2) of a 4-digit clock module on or off. It
+// stays lit when the content changes, unlike the colon separating the fields
+// of SetClock, SetDuration or SetPair.
+//
+// Returns ErrNotConfigured if neither ColonSegments nor a ColonPin are
+// configured.
+func (s *SevSeg) SetColon(on bool) error {
+	s.lock()
+	defer s.unlock()
+
+	s.separatorColon = false // Kept until SetColon is called again
+
+	if s.colonPin != nil {
+		s.setIndicatorPin(&s.colonLit, on)
+		return nil
+	}
+
+	return s.setClockIndicator(s.colonSegments, on)
+}
+
+// SetApostrophe turns the apostrophe (L3) of a 4-digit clock module on or
+// off.
+//
+// Returns ErrNotConfigured if neither ApostropheSegments nor an
+// ApostrophePin are configured.
+func (s *SevSeg) SetApostrophe(on bool) error {
+	s.lock()
+	defer s.unlock()
+
+	if s.apostrophePin != nil {
+		s.setIndicatorPin(&s.apostropheLit, on)
+		return nil
 	}
+
+	return s.setClockIndicator(s.apostropheSegments, on)
 }
 
 // SetNumber sets the number to be displayed.
-func (s *SevSeg) SetNumber(number int32) bool {
-	if !s.checkAvailableDigits(number, 10) {
-		return false
+func (s *SevSeg) SetNumber(number int32) error {
+	s.lock()
+	defer s.unlock()
+
+	if err := s.format.Number(s.updatedDisplay, number); err != nil {
+		return err
 	}
 
-	s.setNumberInitPattern()
+	s.highlightChange(number)
+	s.commitNumber(ContentNumber, number < 0)
 
-	isNegative := number < 0
-	if isNegative {
-		number = -number
+	return nil
+}
+
+// UpdateLowestDigits updates only the n least significant digits with the
+// given value, zero padded to n digits. The remaining digits and all decimal
+// points are kept.
+//
+// This is meant for the hot path of high-rate counters: format the full
+// number once with SetNumber, then update the fast changing digits with this
+// method, avoiding a full re-format.
+func (s *SevSeg) UpdateLowestDigits(n uint8, value uint32) error {
+	s.lock()
+	defer s.unlock()
+
+	if n == 0 || n > uint8(len(s.digitPins)) {
+		return ErrOutOfRange
 	}
 
-	position := 0
-	if number == 0 {
-		s.updatedDisplay[position] = s.getSegmentCode(0) // ZERO
-	} else {
-		for number > 0 && position < len(s.digitPins) {
-			digit := uint8(number % 10)
-			s.updatedDisplay[position] = s.getSegmentCode(digit)
-			number /= 10
+	limit := uint64(1)
+	for range n {
+		limit *= 10
+	}
 
-			position++
-		}
+	if uint64(value) >= limit {
+		return ErrTooManyDigits
 	}
 
-	if isNegative {
-		s.updatedDisplay[position] = s.getSegmentCode(37) // MINUS
+	for i := range n {
+		decimalPoint := s.updatedDisplay[i] & s.getSegmentCode(38) // DECIMAL POINT
+		s.updatedDisplay[i] = s.getSegmentCode(uint8(value%10)) | decimalPoint
+		value /= 10
 	}
 
-	return true
+	s.commit(ContentNumber)
+
+	return nil
 }
 
 // SetNumberFloat takes a float number as argument and displays it with a
 // specified number of decimal places.
-func (s *SevSeg) SetNumberFloat(number float32, decimalPlaces uint8) bool {
+func (s *SevSeg) SetNumberFloat(number float32, decimalPlaces uint8) error {
+	s.lock()
+	defer s.unlock()
+
+	// Without the decimal point the number would be misread, e.g. 12.5 as 125
+	if s.segmentCount() < 8 {
+		return ErrNoDecimalPointPin
+	}
+
 	if decimalPlaces <= 0 {
-		return false
+		return ErrInvalidArgument
 	}
 
 	scale := int32(1)
@@ -273,11 +1177,24 @@ func (s *SevSeg) SetNumberFloat(number float32, decimalPlaces uint8) bool {
 
 	scaled := int32(number * float32(scale))
 
-	if !s.SetNumberWithDecimal(scaled, decimalPlaces) {
-		return false
+	if s.trimZeros {
+		for decimalPlaces > 0 && scaled%10 == 0 {
+			scaled /= 10
+			decimalPlaces--
+		}
+
+		if decimalPlaces == 0 {
+			if err := s.format.Number(s.updatedDisplay, scaled); err != nil {
+				return err
+			}
+
+			s.commitNumber(ContentFloat, scaled < 0)
+
+			return nil
+		}
 	}
 
-	return true
+	return s.SetNumberWithDecimal(scaled, decimalPlaces)
 }
 
 // SetNumberWithDecimal sets the number to be displayed, including a decimal
@@ -287,7 +1204,10 @@ func (s *SevSeg) SetNumberFloat(number float32, decimalPlaces uint8) bool {
 // left since the LSB is the right most digit.
 //
 // E.g. for a 4-digit display, decimalPointPosition = 1 would look like this: 000.0
-func (s *SevSeg) SetNumberWithDecimal(number int32, decimalPointPosition uint8) bool {
+func (s *SevSeg) SetNumberWithDecimal(number int32, decimalPointPosition uint8) error {
+	s.lock()
+	defer s.unlock()
+
 	return s.SetNumberWithMultipleDecimals(number, []uint8{decimalPointPosition})
 }
 
@@ -299,97 +1219,236 @@ func (s *SevSeg) SetNumberWithDecimal(number int32, decimalPointPosition uint8)
 //
 // E.g. for a 4-digit display, decimalPointsPositions = []uint{1, 2} would look like
 // this: 00.0.0
-func (s *SevSeg) SetNumberWithMultipleDecimals(number int32, decimalPointsPositions []uint8) bool {
-	if len(decimalPointsPositions) == 0 {
-		return false
+func (s *SevSeg) SetNumberWithMultipleDecimals(number int32, decimalPointsPositions []uint8) error {
+	s.lock()
+	defer s.unlock()
+
+	if err := s.format.Decimals(s.updatedDisplay, number, decimalPointsPositions); err != nil {
+		return err
 	}
 
-	for _, decimalPos := range decimalPointsPositions {
-		if decimalPos > uint8(len(s.digitPins)) {
-			return false
-		}
+	s.commitNumber(ContentFloat, number < 0)
+
+	return nil
+}
+
+// SetHex sets the number to be displayed as a hexadecimal value.
+func (s *SevSeg) SetHex(number uint32) error {
+	s.lock()
+	defer s.unlock()
+
+	if err := s.format.Hex(s.updatedDisplay, number); err != nil {
+		return err
 	}
 
-	if len(s.segmentPins) < 8 {
-		return false
+	s.commitNumber(ContentHex, false)
+
+	return nil
+}
+
+// SetNumberWithSeparatorPattern displays a number as groups of groupSize
+// digits with a separator between them, e.g. "12-34-56" for date-like codes.
+// Every group is zero padded.
+//
+// If the display is wide enough, each separator takes a digit of its own and
+// is shown as the given character. Otherwise the decimal point is used as the
+// separator.
+func (s *SevSeg) SetNumberWithSeparatorPattern(number uint32, groupSize, groups uint8, separator byte) error {
+	s.lock()
+	defer s.unlock()
+
+	if groupSize == 0 || groups == 0 {
+		return ErrInvalidArgument
 	}
 
-	if !s.SetNumber(number) {
-		return false
+	separatorPattern, ok := s.format.Glyph(separator)
+	if !ok {
+		return ErrUnsupportedChar
 	}
 
-	for _, decimalPos := range decimalPointsPositions {
-		if decimalPos > uint8(len(s.digitPins)) {
-			return false
-		}
+	totalDigits := int(groupSize) * int(groups)
+	if totalDigits > len(s.digitPins) {
+		return ErrTooManyDigits
+	}
 
-		s.updatedDisplay[decimalPos] |= s.getSegmentCode(38) // DECIMAL POINT
+	groupDivisor := uint64(1)
+	for range groupSize {
+		groupDivisor *= 10
 	}
 
-	return true
-}
+	rest := uint64(number)
+	for range groups {
+		rest /= groupDivisor
+	}
 
-// SetHex sets the number to be displayed as a hexadecimal value.
-func (s *SevSeg) SetHex(number uint32) bool {
-	if !s.checkAvailableDigits(int32(number), 16) {
-		return false
+	if rest != 0 {
+		return ErrTooManyDigits
 	}
 
-	s.setNumberInitPattern()
+	frame := make([]uint8, len(s.digitPins))
+
+	if totalDigits+int(groups)-1 <= len(frame) {
+		for i := range frame {
+			frame[i] = s.getSegmentCode(36) // BLANK
+		}
+
+		position := 0
+		for g := range groups {
+			if g > 0 {
+				frame[position] = separatorPattern
+				position++
+			}
 
-	position := 0
-	if number == 0 {
-		s.updatedDisplay[position] = s.getSegmentCode(0) // ZERO
+			s.format.Digits(frame[position:position+int(groupSize)], uint32(uint64(number)%groupDivisor), false, 10, groupSize)
+			number = uint32(uint64(number) / groupDivisor)
+			position += int(groupSize)
+		}
 	} else {
-		for number > 0 && position < len(s.digitPins) {
-			digit := uint8(number) % 16
-			s.updatedDisplay[position] = s.getSegmentCode(digit)
-			number /= 16
-			position++
+		if s.segmentCount() < 8 {
+			return ErrNoDecimalPointPin
+		}
+
+		s.format.Digits(frame, number, false, 10, uint8(totalDigits))
+		for g := 1; g < int(groups); g++ {
+			frame[g*int(groupSize)] |= s.getSegmentCode(38) // DECIMAL POINT
 		}
 	}
 
-	return true
+	copy(s.updatedDisplay, frame)
+	s.commitNumber(ContentNumber, false)
+
+	return nil
 }
 
-// SetTemperature sets the temperature to be displayed with a ° character.
-func (s *SevSeg) SetTemperature(temperature float32, decimalPlaces uint8) bool {
+// SetUptime displays an elapsed time in seconds, e.g. for service counters.
+//
+// The finest of the following formats which fits the display is chosen, so
+// the format changes as the value grows:
+//
+//	seconds, M.SS, H.MM, D.HH
+//
+// The right most digit shows a marker for the format in use: '_' for seconds,
+// '-' for M.SS, '‾' for H.MM and both the top and the bottom bar (segments A
+// and D) for D.HH. The formats with a separator require the decimal point
+// pin.
+func (s *SevSeg) SetUptime(seconds uint32) error {
+	s.lock()
+	defer s.unlock()
+
 	if len(s.digitPins) <= 1 {
-		return false // We need at least 2 digits to display a number
+		return ErrTooManyDigits // We need at least 2 digits, one for the marker
 	}
 
-	scale := int32(1)
-	for range decimalPlaces + 1 { // Additional *10 for the ° Character
-		scale *= 10
+	markers := []uint8{
+		0b00001000, // seconds: bottom bar
+		0b01000000, // M.SS: middle bar
+		0b00000001, // H.MM: top bar
+		0b00001001, // D.HH: top and bottom bar
 	}
 
-	scaled := int32(temperature * float32(scale))
+	for i, marker := range markers {
+		if i > 0 && s.segmentCount() < 8 {
+			break // No decimal point to separate the fields
+		}
 
-	if !s.checkAvailableDigits(int32(scaled), 10) {
-		return false
-	}
+		// The last field holds the rest, e.g. the total minutes of M.SS
+		fields := sexagesimalFields(seconds, i+1)
+		shown := fields[max(i-1, 0) : i+1]
 
-	if decimalPlaces > 0 {
-		// Scale temperature by 10 to reserve space for ° symbol
-		if !s.SetNumberWithDecimal(scaled, decimalPlaces+1) {
-			return false
-		}
-	} else {
-		if !s.SetNumber(scaled) {
-			return false
+		if s.format.sexagesimal(s.updatedDisplay[1:], shown, len(shown), 1, false) != nil {
+			continue
 		}
+
+		s.updatedDisplay[0] = marker
+		s.commitNumber(ContentNumber, false)
+
+		return nil
 	}
 
-	s.updatedDisplay[0] = s.getSegmentCode(39) // DEGREE
+	return ErrTooManyDigits
+}
 
-	return true
+// SetSexagesimal displays a duration in seconds in the D.HH.MM.SS style,
+// e.g. 3725 seconds as 1.02.05. Leading fields which are zero are omitted,
+// all other fields except the first one are zero padded to two digits. The
+// fields are separated by the decimal point.
+func (s *SevSeg) SetSexagesimal(totalSeconds uint32) error {
+	s.lock()
+	defer s.unlock()
+
+	if err := s.format.Sexagesimal(s.updatedDisplay, totalSeconds); err != nil {
+		return err
+	}
+
+	s.commitNumber(ContentNumber, false)
+
+	return nil
+}
+
+// SetFraction displays a fraction, e.g. "3-4" for 3/4, for imperial
+// measurements where decimal output isn't wanted. If the display is too narrow
+// for the separator, the decimal point is used instead ("3.4"). The fraction
+// is shown as given, it's not reduced.
+func (s *SevSeg) SetFraction(numerator, denominator uint32) error {
+	s.lock()
+	defer s.unlock()
+
+	if denominator == 0 {
+		return ErrInvalidArgument
+	}
+
+	numeratorDigits := decimalDigits(numerator)
+	denominatorDigits := decimalDigits(denominator)
+	frame := make([]uint8, len(s.digitPins))
+
+	switch {
+	case numeratorDigits+1+denominatorDigits <= len(frame):
+		s.format.Digits(frame[denominatorDigits+1:], numerator, false, 10, 1)
+		frame[denominatorDigits] = s.getSegmentCode(37) // MINUS
+	case numeratorDigits+denominatorDigits > len(frame):
+		return ErrTooManyDigits
+	case s.segmentCount() < 8:
+		return ErrNoDecimalPointPin
+	default:
+		s.format.Digits(frame[denominatorDigits:], numerator, false, 10, 1)
+		frame[denominatorDigits] |= s.getSegmentCode(38) // DECIMAL POINT
+	}
+
+	s.format.Digits(frame[:denominatorDigits], denominator, false, 10, 1)
+	copy(s.updatedDisplay, frame)
+	s.commitNumber(ContentNumber, false)
+
+	return nil
+}
+
+// SetTemperature sets the temperature to be displayed with a ° character.
+func (s *SevSeg) SetTemperature(temperature float32, decimalPlaces uint8) error {
+	s.lock()
+	defer s.unlock()
+
+	negative, err := s.format.temperature(s.updatedDisplay, temperature, decimalPlaces)
+	if err != nil {
+		return err
+	}
+
+	s.commitNumber(ContentFloat, negative)
+
+	return nil
 }
 
 // SetTemperatureWithUnit sets the temperature to be displayed in °C or °F.
 // Note that two digits are required to show °C / °F
-func (s *SevSeg) SetTemperatureWithUnit(temperature float32, decimalPlaces uint8, unit tempUnit) bool {
+func (s *SevSeg) SetTemperatureWithUnit(temperature float32, decimalPlaces uint8, unit tempUnit) error {
+	s.lock()
+	defer s.unlock()
+
 	if len(s.digitPins) <= 2 {
-		return false // We need at least 3 digits to display a number
+		return ErrTooManyDigits // We need at least 3 digits to display a number
+	}
+
+	// Degrade to whole degrees without the decimal point
+	if s.segmentCount() < 8 {
+		decimalPlaces = 0
 	}
 
 	// Scale temperature by 10 to reserve space for unit symbol (C/F)
@@ -397,8 +1456,9 @@ func (s *SevSeg) SetTemperatureWithUnit(temperature float32, decimalPlaces uint8
 	if decimalPlaces > 0 {
 		adjustedDecimalPlaces++ // Move decimal point
 	}
-	if !s.SetTemperature(temperature*10, adjustedDecimalPlaces) {
-		return false
+	negative, err := s.format.temperature(s.updatedDisplay, temperature*10, adjustedDecimalPlaces)
+	if err != nil {
+		return err
 	}
 
 	s.updatedDisplay[1] = s.getSegmentCode(39) // DEGREE
@@ -407,17 +1467,126 @@ func (s *SevSeg) SetTemperatureWithUnit(temperature float32, decimalPlaces uint8
 		s.updatedDisplay[0] = s.getSegmentCode(15) // 'F'
 	}
 
-	return true
+	s.commitNumber(ContentFloat, negative)
+
+	return nil
 }
 
-// SetSegment can be used to display any arbitrary segment pattern.
-// E.g. to display the following pattern:
-//
-//		‾│ │‾  │‾  ‾│
-//		_|.│_  │_. _│
-//	    4   3   2  1
+// SetTemperatureWithTrend sets the temperature to be displayed with a °
+// character and a trend indicator on the left most digit.
 //
-// Would equal the bit pattern:
+// The trend is approximated by a single segment: A for rising, D for falling
+// and G for a stable temperature. Note that three digits are required.
+func (s *SevSeg) SetTemperatureWithTrend(temperature float32, decimalPlaces uint8, trend trend) error {
+	s.lock()
+	defer s.unlock()
+
+	displayWidth := len(s.digitPins)
+	if displayWidth <= 2 {
+		return ErrTooManyDigits // We need at least 3 digits to display a number
+	}
+
+	negative, err := s.format.temperature(s.updatedDisplay[:displayWidth-1], temperature, decimalPlaces)
+	if err != nil {
+		return err
+	}
+
+	s.updatedDisplay[displayWidth-1] = uint8(trend)
+	s.commitNumber(ContentFloat, negative)
+
+	return nil
+}
+
+// SetTemperatureRaw sets a temperature in °C given as fixed point with
+// fracBits fraction bits, e.g. the 1/16 °C (fracBits 4) of a DS18B20, followed
+// by °C or °F. It uses integer math only, which matters on chips without an
+// FPU. The temperature is converted for TemperatureUnit.Fahrenheit and shown
+// with one decimal place if it fits, rounded to a whole degree otherwise.
+// Note that three digits are required.
+func (s *SevSeg) SetTemperatureRaw(raw int16, fracBits uint8, unit tempUnit) error {
+	s.lock()
+	defer s.unlock()
+
+	if len(s.digitPins) <= 2 {
+		return ErrTooManyDigits // We need at least 3 digits to display a number
+	}
+
+	if fracBits > 15 {
+		return ErrInvalidArgument
+	}
+
+	return s.setTemperatureTenths(fixedToTenths(raw, fracBits, unit == TemperatureUnit.Fahrenheit), unit)
+}
+
+// setTemperatureTenths shows a temperature in tenths of a degree followed by
+// °C or °F, with one decimal place if it fits and rounded to a whole degree
+// otherwise. The display must have at least three digits.
+func (s *SevSeg) setTemperatureTenths(tenths int32, unit tempUnit) error {
+	magnitude := uint32(tenths)
+	if tenths < 0 {
+		magnitude = uint32(-tenths)
+	}
+
+	// Two digits are reserved for the unit, the tenths are zero padded so
+	// that e.g. 0.5 isn't shown as .5
+	dst := s.updatedDisplay[2:]
+	negative := tenths < 0
+	err := ErrNoDecimalPointPin
+	if s.segmentCount() == 8 {
+		err = s.format.Digits(dst, magnitude, negative, 10, 2)
+	}
+
+	if err == nil {
+		dst[1] |= s.getSegmentCode(38) // DECIMAL POINT
+	} else {
+		negative = tenths <= -5
+		if err := s.format.Digits(dst, (magnitude+5)/10, negative, 10, 1); err != nil {
+			return err
+		}
+	}
+
+	s.updatedDisplay[1] = s.getSegmentCode(39) // DEGREE
+	s.updatedDisplay[0] = s.getSegmentCode(12) // 'C'
+	if unit == TemperatureUnit.Fahrenheit {
+		s.updatedDisplay[0] = s.getSegmentCode(15) // 'F'
+	}
+
+	s.commitNumber(ContentFloat, negative)
+
+	return nil
+}
+
+// fixedToTenths converts a fixed point temperature in °C with fracBits
+// fraction bits to tenths of a degree in °C or °F, rounded half away from
+// zero.
+func fixedToTenths(raw int16, fracBits uint8, fahrenheit bool) int32 {
+	factor := int32(10)
+	if fahrenheit {
+		factor = 18 // 10 * 9/5
+	}
+
+	scaled := int32(raw) * factor
+	half := int32(1) << fracBits >> 1
+	if scaled < 0 {
+		half = -half
+	}
+
+	tenths := (scaled + half) / (int32(1) << fracBits)
+	if fahrenheit {
+		tenths += 320
+	}
+
+	return tenths
+}
+
+// SetSegment can be used to display any arbitrary segment pattern.
+// E.g. to display the following pattern:
+//
+//		‾│ │‾  │‾  ‾│
+//		_|.│_  │_. _│
+//	    4   3   2  1
+//
+// Would equal the bit pattern:
 //
 // {0b00001111, 0b10111001, 0b00111001, 0b10001111}
 //
@@ -431,59 +1600,130 @@ func (s *SevSeg) SetTemperatureWithUnit(temperature float32, decimalPlaces uint8
 // The segments will be displayed from right to left. This means that if fewer
 // segments are defined than digits available, the remaining segments (on the
 // left) will be cleared.
-func (s *SevSeg) SetSegment(pattern []uint8) bool {
+//
+// Returns ErrNoDecimalPointPin if a pattern has the DP bit set but the
+// display lacks the decimal point pin, unless MaskDecimalPoint is set.
+func (s *SevSeg) SetSegment(pattern []uint8) error {
+	s.lock()
+	defer s.unlock()
+
 	if len(pattern) > len(s.digitPins) {
-		return false
+		return ErrTooManyDigits
+	}
+
+	if err := s.checkDecimalPoints(pattern); err != nil {
+		return err
 	}
 
 	copy(s.updatedDisplay, pattern)
+	s.maskDecimalPoints(s.updatedDisplay)
+	s.commit(ContentSegment)
 
-	return true
+	return nil
+}
+
+// FrameLiteral returns the current frame as a Go literal which can be passed
+// to SetSegment, e.g. "[]uint8{0b00111111, 0b00000110}" after SetNumber(10).
+// This helps designing custom animations by capturing frames from the
+// formatting functions instead of calculating bit patterns by hand:
+//
+//	display.SetText("HI")
+//	println(display.FrameLiteral())
+func (s *SevSeg) FrameLiteral() string {
+	s.lock()
+	defer s.unlock()
+
+	literal := []byte("[]uint8{")
+
+	for i, pattern := range s.updatedDisplay {
+		if i > 0 {
+			literal = append(literal, ", "...)
+		}
+
+		literal = append(literal, "0b"...)
+		for bit := 7; bit >= 0; bit-- {
+			literal = append(literal, '0'+(pattern>>bit)&1)
+		}
+	}
+
+	return string(append(literal, '}'))
 }
 
 // SetText displays a text.
 //
-// If the text is longer than the number of digits, an error is returned.
+// Returns ErrUnsupportedChar if the text contains a character which can't be
+// displayed.
 //
 // The text is written from left to right, meaning that if the text is shorter
 // than the number of digits, the remaining segments (on the right) will be cut
 // off. You can use ScrollTextLeft or ScrollTextRight to scroll the text.
-func (s *SevSeg) SetText(text string) bool {
-	s.Clear()
-
-	s.scrollPosition = 0
+func (s *SevSeg) SetText(text string) error {
+	s.lock()
+	defer s.unlock()
 
-	textLength := len(text)
-	displayWidth := len(s.digitPins)
-	reservedTextLength := textLength
-
-	if textLength > displayWidth {
-		reservedTextLength += displayWidth
+	textPattern, err := s.format.Patterns(text)
+	if err != nil {
+		return err
 	}
-	s.textPattern = make([]uint8, reservedTextLength)
 
-	for i, char := range []byte(text) {
-		segment, ok := s.charToSegmentPattern(char)
-		if !ok {
-			return false
+	displayWidth := len(s.digitPins)
+	if len(textPattern) > displayWidth {
+		for range displayWidth {
+			textPattern = append(textPattern, s.getSegmentCode(36)) // BLANK
 		}
-		s.textPattern[i] = segment
 	}
 
-	if textLength > displayWidth {
-		for i := range displayWidth {
-			s.textPattern[textLength+i] = s.getSegmentCode(36) // BLANK
-		}
+	s.textPattern = textPattern
+	s.textString = ""
+	s.scrollPosition = 0
+	s.scheduleScroll()
+
+	s.updateDisplayFromPatterns()
+	s.commit(ContentText)
+
+	return nil
+}
+
+// SetTextPatterns is like SetText, but takes the text as segment patterns in
+// the format of SetSegment, one byte per digit from left to right. Like with
+// PlayFrameString, the patterns are read in place, so a long scrolling text
+// stored in a string constant takes no RAM beyond the string header:
+//
+//	const hello = "\x76\x79\x38\x38\x3f" // HELLO
+//
+//	display.SetTextPatterns(hello)
+//
+// The text can be scrolled with ScrollTextLeft and ScrollTextRight.
+func (s *SevSeg) SetTextPatterns(patterns string) error {
+	s.lock()
+	defer s.unlock()
+
+	if len(patterns) == 0 {
+		return ErrInvalidArgument
 	}
 
+	s.textPattern = nil
+	s.textString = patterns
+	s.scrollPosition = 0
+	s.scheduleScroll()
+
 	s.updateDisplayFromPatterns()
+	s.commit(ContentText)
 
-	return true
+	return nil
 }
 
 // ScrollTextLeft scrolls the text to the left by one digit/segment.
 func (s *SevSeg) ScrollTextLeft() {
-	patternLength := len(s.textPattern)
+	s.lock()
+	defer s.unlock()
+
+	s.scrollTextLeft()
+}
+
+// scrollTextLeft scrolls the text to the left, also called by AutoScroll.
+func (s *SevSeg) scrollTextLeft() {
+	patternLength := s.textLength()
 
 	if patternLength <= len(s.digitPins) {
 		return
@@ -492,11 +1732,15 @@ func (s *SevSeg) ScrollTextLeft() {
 	s.scrollPosition = (s.scrollPosition + 1) % patternLength
 
 	s.updateDisplayFromPatterns()
+	s.commit(ContentText)
 }
 
 // ScrollTextRight scrolls the text to the right by one digit/segment.
 func (s *SevSeg) ScrollTextRight() {
-	patternLength := len(s.textPattern)
+	s.lock()
+	defer s.unlock()
+
+	patternLength := s.textLength()
 
 	if patternLength <= len(s.digitPins) {
 		return
@@ -505,220 +1749,532 @@ func (s *SevSeg) ScrollTextRight() {
 	s.scrollPosition = (s.scrollPosition - 1 + patternLength) % patternLength
 
 	s.updateDisplayFromPatterns()
+	s.commit(ContentText)
 }
 
 // Refresh updates the display. Must be called periodically, ideally with >100Hz
 // to avoid flicker.
 func (s *SevSeg) Refresh() bool {
+	s.lock()
+	lit := s.refresh()
+	s.unlock()
+
+	return lit
+}
+
+// refresh updates the display, called by Refresh and the auto refresh.
+func (s *SevSeg) refresh() bool {
 	if len(s.updatedDisplay) == 0 {
 		return false
 	}
 
-	s.clearDigitPins()
+	s.recordRefreshCall()
 
-	if s.pwm == SoftwarePWM {
-		s.softwarePWM()
-	} else {
-		// s.hardwarePWM()
+	if s.throttled() {
+		return s.lit
 	}
 
+	s.lit = false
+
+	// The pins have been cleared by Off, the effects are paused until On
 	if !s.enabled {
 		return false
 	}
 
-	s.setSegmentPins()
+	if s.backend == nil {
+		s.clearDigitPins()
+		s.flushBanks()
+	}
+
+	if s.windowDue {
+		s.openSharedPinWindow()
+	}
 
-	// Turn on the current digit
-	if s.currentDigitToRefresh < uint8(len(s.digitPins)) {
-		if s.config == CommonCathode {
-			s.digitPins[s.currentDigitToRefresh].Low()
-		} else {
-			s.digitPins[s.currentDigitToRefresh].High()
+	if s.advanceClock() {
+		s.statsFrames++
+		s.tick()
+		s.applyStagedFrame()
+	}
+
+	// A controller multiplexes the digits itself, it only needs the content
+	if s.backend != nil {
+		s.pushFrame()
+		s.dirty = false
+		s.lit = s.shown() && s.currentBrightness() > 0
+
+		return s.lit
+	}
+
+	// With HardwarePWM the brightness is the duty cycle of the lit digit
+	pwmOn := true
+	if s.pwm == SoftwarePWM {
+		pwmOn = s.softwarePWM()
+	}
+
+	if !pwmOn || !s.shown() {
+		// The duty cycle of the compensation differs per digit, so an off
+		// step passes on to the next digit instead of waiting for an on step.
+		// A hidden display keeps scanning, so the effects clock goes on.
+		if s.segmentDuty || !s.shown() {
+			s.nextSlot()
 		}
+
+		return false
+	}
+
+	// Give the previous digit line time to turn off completely
+	if s.deadTime > 0 {
+		busyWait(s.deadTime)
+	}
+
+	if s.scanSegments {
+		s.lightSegmentLine()
+	} else {
+		// All digits are off at this point, which is required for shift
+		// registers without a latch: their outputs change with every bit
+		// shifted in.
+		s.setSegmentPins()
+		s.flushBanks()
+
+		// Turn on the current digit (or aux LED group)
+		s.setDigitPin(s.slotPin(s.currentDigitToRefresh), true)
+		s.flushBanks()
+	}
+
+	s.lightIndicatorPins()
+
+	if s.onTime > 0 {
+		s.endOnTime()
 	}
 
-	s.currentDigitToRefresh = (s.currentDigitToRefresh + 1) % uint8(len(s.digitPins))
+	s.nextSlot()
+	s.lit = true
 
 	return true
 }
 
-// checkAvailableDigits checks if the number can fit within the specified number
-// of digits.
-func (s *SevSeg) checkAvailableDigits(number int32, base uint8) bool {
-	count := uint8(1)
+// nextSlot advances the multiplexing to the next slot.
+func (s *SevSeg) nextSlot() {
+	// A digit with a mixed color is shown once per bus of the color
+	if s.colors == nil || !s.nextColorBus() {
+		s.currentDigitToRefresh = (s.currentDigitToRefresh + 1) % s.scanSteps()
+		if s.currentDigitToRefresh == 0 {
+			s.dirty = false // A full frame has been shown
+			s.frameStart = true
+
+			if s.segmentDuty {
+				s.advancePWM()
+			}
+
+			s.windowDue = s.shareWindow != nil
+		}
 
-	if number == 0 {
-		return count <= uint8(len(s.digitPins))
+		if s.colors != nil {
+			s.firstColorBus()
+		}
 	}
+}
 
-	if number < 0 {
-		count++
-		number = -number
+// commit is called whenever the content of the display has been updated.
+func (s *SevSeg) commit(kind contentType) {
+	switch kind {
+	case ContentNone, ContentText, ContentSegment, ContentAnimation:
+		s.setSignElement(false) // Only numbers light the sign element
+		s.setSeparatorColon(false)
 	}
 
-	for ; number > 0; number /= int32(base) {
-		count++
+	if kind != ContentAnimation {
+		s.stopFrames() // New content replaces the playback
+	}
+
+	s.endClockFace() // SetClock starts it again after committing
+	s.contentType = kind
+	s.idleSince = s.clock
+	s.stageFrame()
+
+	if s.batchDepth > 0 {
+		s.batchChanged = true // Published by End
+	} else {
+		s.publishFrame()
 	}
-	count--
 
-	return count <= uint8(len(s.digitPins))
+	s.invalidate()
 }
 
-// charToSegmentPattern converts a character to its corresponding segment
-// pattern.
-func (s *SevSeg) charToSegmentPattern(char byte) (uint8, bool) {
-	if char >= 'a' && char <= 'z' {
-		// Since we can't differ between upper and lower case letters, we
-		// convert lower-case letters to upper-case.
-		char = char - 'a' + 'A'
+// commitNumber commits a number, lighting the sign element if it's negative.
+// Numbers which are never negative clear it. The colon separating the fields
+// of the previous content is turned off.
+func (s *SevSeg) commitNumber(kind contentType, negative bool) {
+	s.setSignElement(negative)
+	s.setSeparatorColon(false)
+	s.commit(kind)
+}
+
+// commitSeparated commits a number whose fields are separated by the colon,
+// e.g. the hours and minutes of SetClock, lighting it.
+func (s *SevSeg) commitSeparated() {
+	s.setSignElement(false)
+	s.setSeparatorColon(true)
+	s.commit(ContentNumber)
+}
+
+// publishFrame records the committed frame in the history and sends it to the
+// mirror driver.
+func (s *SevSeg) publishFrame() {
+	s.recordHistory()
+
+	if s.mirror != nil {
+		s.mirror.WriteFrame(s.updatedDisplay)
 	}
+}
 
-	switch {
-	case char >= '0' && char <= '9':
-		return s.getSegmentCode(char - '0'), true
-	case char >= 'A' && char <= 'Z':
-		return s.getSegmentCode(char - 'A' + 10), true
-	case char == ' ':
-		return s.getSegmentCode(36), true
-	case char == '-':
-		return s.getSegmentCode(37), true
-	case char == '.':
-		return s.getSegmentCode(38), true
-	case char == '*':
-		return s.getSegmentCode(39), true
-	case char == '_':
-		return s.getSegmentCode(40), true
-	}
-
-	return 0, false
-}
-
-// clearDigitPins turns off all digit pins.
+// setSignElement lights the minus of the dedicated sign element for negative
+// numbers and clears it otherwise.
+func (s *SevSeg) setSignElement(isNegative bool) {
+	if s.signSegments == 0 {
+		return
+	}
+
+	sign := len(s.auxDisplay) - 1
+	s.auxDisplay[sign] = 0
+	if isNegative {
+		s.auxDisplay[sign] = s.signSegments
+	}
+}
+
+// setClockIndicator turns the given segments of the clock indicator on or
+// off.
+func (s *SevSeg) setClockIndicator(segments uint8, on bool) error {
+	if segments == 0 {
+		return ErrNotConfigured
+	}
+
+	s.endClockFace() // Before the change, which would be overwritten otherwise
+
+	indicator := s.auxLEDGroups
+	if on {
+		s.auxDisplay[indicator] |= segments
+	} else {
+		s.auxDisplay[indicator] &^= segments
+	}
+
+	s.commit(s.contentType)
+
+	return nil
+}
+
+// clearDigitPins turns off all digit pins, including the aux digit pins.
 func (s *SevSeg) clearDigitPins() {
-	for _, pin := range s.digitPins {
-		if s.config == CommonCathode {
-			pin.High()
-		} else {
-			pin.Low()
-		}
+	for i := range s.slotCount() {
+		s.setDigitPin(s.slotPin(i), false)
+	}
+
+	s.clearIndicatorPins()
+}
+
+// flushBanks writes the pins of all PinBanks, e.g. GPIO expanders.
+func (s *SevSeg) flushBanks() {
+	for _, bank := range s.banks {
+		bank.Flush()
 	}
 }
 
 // clearSegmentPins turns off all segment pins.
 func (s *SevSeg) clearSegmentPins() {
-	for _, pin := range s.segmentPins {
-		if s.config == CommonCathode {
-			pin.Low()
-		} else {
-			pin.High()
+	if s.shiftRegister != NoShiftRegister {
+		s.shiftOut(0)
+		return
+	}
+
+	if s.decoder != NoDecoder {
+		s.writeBCD(0)
+		return
+	}
+
+	for _, pins := range s.colorBusPins {
+		for _, pin := range pins {
+			s.setSegmentPin(pin, false)
 		}
 	}
 }
 
-// configurePWM sets up PWM channels for segment pins if HardwarePWM is used.
-// func (s *SevSeg) configurePWM(pwmPins []machine.PWM) bool {
-// 	for _, timer := range pwmPins {
-// 		timer.Configure(machine.PWMConfig{})
-// 	}
+// configurePWM sets up PWM channels for the digit (and aux digit) pins if
+// HardwarePWM is used. Returns false if a timer can't be configured or a pin
+// isn't an output of any timer.
+func (s *SevSeg) configurePWM(pwmPins []PWM) bool {
+	for _, timer := range pwmPins {
+		if timer.Configure(machinePWMConfig{Period: hardwarePWMPeriod}) != nil {
+			return false
+		}
+	}
 
-// 	for _, segmentPin := range s.segmentPins {
-// 		found := false
-// 		for _, timer := range pwmPins {
-// 			if ch, err := timer.Channel(segmentPin); err == nil {
-// 				s.pwmChannels[segmentPin] = pwmChannelMap{pwm: timer, channel: ch}
-// 				found = true
-// 				break
-// 			}
-// 		}
-// 		if !found {
-// 			return false
-// 		}
-// 	}
-// 	return true
-// }
+	s.pwmChannels = make(map[OutputPin]pwmChannelMap, s.slotCount())
+	for i := range s.slotCount() {
+		pin, ok := s.slotPin(i).(machinePin)
+		if !ok {
+			return false // Only GPIO pins can be driven by a timer
+		}
 
-// setNumberInitPattern sets the initial pattern for the display when a number
-// is set.
-func (s *SevSeg) setNumberInitPattern() {
-	initPattern := s.getSegmentCode(36) // BLANK
-	if s.useLeadingZeros {
-		initPattern = s.getSegmentCode(0) // ZERO
-	}
+		found := false
+		for _, timer := range pwmPins {
+			if ch, err := timer.Channel(pin); err == nil {
+				// The digit pins of a common cathode display are active low
+				timer.SetInverting(ch, s.config == CommonCathode)
+				timer.Set(ch, 0)
+				s.pwmChannels[pin] = pwmChannelMap{pwm: timer, channel: ch}
+				found = true
+				break
+			}
+		}
 
-	for i := range s.updatedDisplay {
-		s.updatedDisplay[i] = initPattern
+		if !found {
+			return false
+		}
 	}
+
+	return true
 }
 
 // setSegmentPins sets the segment pins according to the current digit to
 // refresh and the updated display pattern.
 func (s *SevSeg) setSegmentPins() {
+	pattern := s.slotPattern(s.currentDigitToRefresh)
+
+	if s.colors != nil {
+		s.setColorSegmentPins(pattern)
+		return
+	}
+
+	if s.decoder != NoDecoder {
+		s.writeBCD(pattern)
+		return
+	}
+
+	if s.shiftRegister != NoShiftRegister {
+		s.shiftOut(pattern)
+		return
+	}
+
+	if s.useSegmentPort {
+		// The segment pins of a common anode display are active low
+		s.segmentPort.write(pattern, s.config == CommonAnode)
+		return
+	}
+
 	for i, pin := range s.segmentPins {
-		pattern := s.updatedDisplay[s.currentDigitToRefresh]
-		segmentOn := (pattern & (1 << i)) != 0
+		s.setSegmentPin(pin, (pattern&(1<<i)) != 0)
+	}
+}
 
-		if s.config == CommonCathode {
-			if segmentOn {
-				pin.High()
-			} else {
-				pin.Low()
-			}
-		} else { // CommonAnode
-			if segmentOn {
-				pin.Low()
-			} else {
-				pin.High()
-			}
+// shiftOut shifts a segment pattern into the shift register and latches it
+// if the shift register has a latch. The DP bit is shifted first, so that
+// segment A ends up at Q0.
+func (s *SevSeg) shiftOut(pattern uint8) {
+	if s.shiftSPI != nil {
+		// The segment lines of a common anode display are active low
+		s.shiftBuf[0] = pattern
+		if s.config == CommonAnode {
+			s.shiftBuf[0] = ^pattern
+		}
+
+		s.shiftSPI.Tx(s.shiftBuf[:], nil)
+	} else {
+		for i := 7; i >= 0; i-- {
+			s.setSegmentPin(s.shiftDataPin, (pattern&(1<<i)) != 0)
+			s.shiftClockPin.High()
+			s.shiftClockPin.Low()
+		}
+	}
+
+	if s.shiftRegister == ShiftRegister74HC595 {
+		s.shiftLatchPin.High()
+		s.shiftLatchPin.Low()
+	}
+}
+
+// segmentCount returns the number of segment lines, i.e. 8 if a shift
+// register or a controller is used and the number of SegmentPins otherwise.
+func (s *SevSeg) segmentCount() uint8 {
+	if s.shiftRegister != NoShiftRegister || s.backend != nil && !s.pioRefresh {
+		return 8
+	}
+
+	// The data inputs of a decoder, followed by the decimal point
+	if s.decoder != NoDecoder {
+		return uint8(len(s.segmentPins)-4) + 7
+	}
+
+	return uint8(len(s.segmentPins))
+}
+
+// checkDecimalPoints returns ErrNoDecimalPointPin if a pattern has the DP bit
+// set, but the display lacks the decimal point pin and MaskDecimalPoint isn't
+// set.
+func (s *SevSeg) checkDecimalPoints(patterns []uint8) error {
+	for _, pattern := range patterns {
+		if err := s.checkDecimalPoint(pattern); err != nil {
+			return err
 		}
 	}
+
+	return nil
+}
+
+// checkDecimalPoint is checkDecimalPoints for a single pattern.
+func (s *SevSeg) checkDecimalPoint(pattern uint8) error {
+	if pattern&0b10000000 != 0 && !s.maskDP && !s.HasDecimalPoint() {
+		return ErrNoDecimalPointPin
+	}
+
+	return nil
 }
 
-// hardwarePWM is a hardware controlled PWM that sets the segments on the
-// display with the according brightness.
-// func (s *SevSeg) hardwarePWM() {
-// 	s.enabled = s.brightness > 0
+// maskDecimalPoints clears the DP bit of the patterns if the display lacks
+// the decimal point pin.
+func (s *SevSeg) maskDecimalPoints(patterns []uint8) {
+	if s.HasDecimalPoint() {
+		return
+	}
 
-// 	if !s.enabled {
-// 		return
-// 	}
+	for i := range patterns {
+		patterns[i] &^= 0b10000000
+	}
+}
+
+// setDigitPin turns a digit pin on or off, depending on the display type.
+func (s *SevSeg) setDigitPin(pin OutputPin, on bool) {
+	if channelMap, exists := s.pwmChannels[pin]; exists {
+		s.hardwarePWM(channelMap, on)
+		return
+	}
 
-// 	// FIXME:
-// 	if s.currentDigitToRefresh < uint8(len(s.digitPins)) {
-// 		if channelMap, exists := s.pwmChannels[s.digitPins[s.currentDigitToRefresh]]; exists {
-// 			duty := (channelMap.pwm.Top() * uint32(s.brightness)) / 100
-// 			channelMap.pwm.Set(channelMap.channel, duty)
-// 		}
-// 	}
-// }
+	// The digit pins of a common cathode display are active low
+	setPin(pin, on != (s.config == CommonCathode))
+}
+
+// setSegmentPin turns a segment pin on or off, depending on the display type.
+func (s *SevSeg) setSegmentPin(pin OutputPin, on bool) {
+	// The segment pins of a common anode display are active low
+	setPin(pin, on != (s.config == CommonAnode))
+}
+
+// slotCount returns the number of multiplexed slots, i.e. the digits followed
+// by the aux digits.
+func (s *SevSeg) slotCount() uint8 {
+	return uint8(len(s.digitPins) + len(s.auxDigitPins))
+}
+
+// slotPin returns the common pin of the given multiplex slot.
+func (s *SevSeg) slotPin(slot uint8) OutputPin {
+	if slot < uint8(len(s.digitPins)) {
+		return s.digitPins[slot]
+	}
+
+	return s.auxDigitPins[slot-uint8(len(s.digitPins))]
+}
+
+// slotPattern returns the segment pattern of the given multiplex slot.
+func (s *SevSeg) slotPattern(slot uint8) uint8 {
+	if pattern, ok := s.batchPattern(slot); ok {
+		return pattern
+	}
+
+	if slot < uint8(len(s.digitPins)) {
+		digit := s.burnInDigit(slot)
+		if s.upsideDown {
+			return s.avoidDeadSegments(slot, rotateSegments(s.annotatedPattern(uint8(len(s.digitPins))-1-digit)))
+		}
+
+		return s.avoidDeadSegments(slot, s.annotatedPattern(digit))
+	}
+
+	return s.auxDisplay[slot-uint8(len(s.digitPins))]
+}
+
+// annotatedPattern returns the segment pattern of a digit with its annotation.
+func (s *SevSeg) annotatedPattern(digit uint8) uint8 {
+	if s.annotations == nil {
+		return s.digitPattern(digit)
+	}
+
+	return s.digitPattern(digit) | s.annotations[digit]
+}
+
+// digitPattern returns the segment pattern of a digit, taking a warning, an
+// animated digit or alternating content into account.
+func (s *SevSeg) digitPattern(digit uint8) uint8 {
+	if s.showWarning {
+		return s.warningContent[digit]
+	}
+
+	if pattern, ok := s.animatedPattern(digit); ok {
+		return pattern
+	}
+
+	if s.showAlternate {
+		return s.alternateContent[digit]
+	}
+
+	return s.fadedPattern(digit) &^ s.clockSeparatorMask(digit)
+}
+
+// hardwarePWM is a hardware controlled PWM that turns a digit pin on with the
+// according brightness as duty cycle, or off.
+func (s *SevSeg) hardwarePWM(channelMap pwmChannelMap, on bool) {
+	duty := uint32(0)
+	if on {
+		// Timers with a large top value would overflow uint32
+		duty = uint32(uint64(channelMap.pwm.Top()) * uint64(s.segmentDutyCycle(s.currentBrightness())) / 100)
+	}
+
+	channelMap.pwm.Set(channelMap.channel, duty)
+}
 
 // softwarePWM is a software controlled PWM that sets the segments on the
-// display with the according brightness.
-func (s *SevSeg) softwarePWM() {
-	const pwmPeriod = uint8(10)
+// display with the according brightness. Returns whether the display is lit
+// during the current Refresh call.
+func (s *SevSeg) softwarePWM() bool {
+	// With the segment compensation, the PWM advances once per frame instead,
+	// so every digit goes through all steps with its own duty cycle
+	if !s.segmentDuty {
+		s.advancePWM()
+	}
 
-	s.pwmCounter = (s.pwmCounter + 1) % pwmPeriod
+	if s.dither {
+		return s.pwmCounter < s.segmentDutyCycle(s.pwmLevel)
+	}
 
 	// Enable display only during "on" portion of PWM cycle
-	// Special cases: 0 = always off, 10 = always on
-	brightnessLevel := (s.brightness + 9) / 10
-	s.enabled = brightnessLevel > 0 && (brightnessLevel >= 10 || s.pwmCounter < brightnessLevel)
+	// Special cases: 0 = always off, pwmSteps = always on
+	brightness := s.segmentDutyCycle(s.currentBrightness())
+	brightnessLevel := uint8((uint16(brightness)*uint16(s.pwmSteps) + 99) / 100)
+	return brightnessLevel > 0 && (brightnessLevel >= s.pwmSteps || s.pwmCounter < brightnessLevel)
+}
+
+// advancePWM advances the software PWM to its next step.
+func (s *SevSeg) advancePWM() {
+	s.pwmCounter = (s.pwmCounter + 1) % s.pwmSteps
+
+	if s.dither && s.pwmCounter == 0 {
+		s.pwmLevel = s.ditheredLevel(s.currentBrightness())
+	}
 }
 
 // updateDisplayFromPatterns updates the display buffer from the text pattern.
 func (s *SevSeg) updateDisplayFromPatterns() {
 	displayWidth := len(s.digitPins)
-	patternLength := len(s.textPattern)
+	patternLength := s.textLength()
 
 	if patternLength > displayWidth {
 		for i := 0; i < displayWidth; i++ {
 			patternIndex := (s.scrollPosition + i) % patternLength
-			s.updatedDisplay[displayWidth-1-i] = s.textPattern[patternIndex]
+			s.updatedDisplay[displayWidth-1-i] = s.textAt(patternIndex)
 		}
 	} else {
 		blankPattern := s.getSegmentCode(36) // BLANK
 		for i := 0; i < displayWidth; i++ {
 			if i < patternLength {
-				s.updatedDisplay[displayWidth-1-i] = s.textPattern[i]
+				s.updatedDisplay[displayWidth-1-i] = s.textAt(i)
 			} else {
 				s.updatedDisplay[displayWidth-1-i] = blankPattern
 			}
@@ -726,54 +2282,35 @@ func (s *SevSeg) updateDisplayFromPatterns() {
 	}
 }
 
-// getSegmentCode returns the segment code for a given index.
+// textLength returns the number of patterns of the text. Like SetText, a text
+// longer than the display is followed by one blank per digit.
+func (s *SevSeg) textLength() int {
+	if s.textString == "" {
+		return len(s.textPattern)
+	}
+
+	length := len(s.textString)
+	if length > len(s.digitPins) {
+		length += len(s.digitPins)
+	}
+
+	return length
+}
+
+// textAt returns the pattern at the given index of the text.
+func (s *SevSeg) textAt(index int) uint8 {
+	if s.textString == "" {
+		return s.textPattern[index]
+	}
+
+	if index < len(s.textString) {
+		return s.textString[index]
+	}
+
+	return s.getSegmentCode(36) // BLANK
+}
+
+// getSegmentCode returns the segment code for a given index of the font.
 func (s *SevSeg) getSegmentCode(index uint8) uint8 {
-	codes := []uint8{
-		// GFEDCBA   Index   ASCII   Symbol   7-segment map:
-		0b00111111, // 0       0      '0'          AAA
-		0b0000110,  // 1       1      '1'         F   B
-		0b01011011, // 2       2      '2'         F   B
-		0b01001111, // 3       3      '3'          GGG
-		0b01100110, // 4       4      '4'         E   C
-		0b01101101, // 5       5      '5'         E   C
-		0b01111101, // 6       6      '6'          DDD
-		0b00000111, // 7       7      '7'
-		0b01111111, // 8       8      '8'
-		0b01101111, // 9       9      '9'
-
-		0b01110111, // 10     65      'A'
-		0b01111100, // 11     66      'b'
-		0b00111001, // 12     67      'C'
-		0b01011110, // 13     68      'd'
-		0b01111001, // 14     69      'E'
-		0b01110001, // 15     70      'F'
-		0b00111101, // 16     71      'G'
-		0b01110110, // 17     72      'H'
-		0b00110000, // 18     73      'I'
-		0b00001110, // 19     74      'J'
-		0b01110110, // 20     75      'K'  Same as 'H'
-		0b00111000, // 21     76      'L'
-		0b00000000, // 22     77      'M'  NO DISPLAY
-		0b01010100, // 23     78      'n'
-		0b00111111, // 24     79      'O'
-		0b01110011, // 25     80      'P'
-		0b01100111, // 26     81      'q'
-		0b01010000, // 27     82      'r'
-		0b01101101, // 28     83      'S'
-		0b01111000, // 29     84      't'
-		0b00111110, // 30     85      'U'
-		0b00111110, // 31     86      'V'  Same as 'U'
-		0b00000000, // 32     87      'W'  NO DISPLAY
-		0b01110110, // 33     88      'X'  Same as 'H'
-		0b01101110, // 34     89      'y'
-		0b01011011, // 35     90      'Z'  Same as '2'
-
-		0b00000000, // 36     32      ' '  BLANK
-		0b01000000, // 37     45      '-'  DASH / MINUS
-		0b10000000, // 38     46      '.'  PERIOD / DECIMAL POINT
-		0b01100011, // 39     42      '°'  DEGREE
-		0b00001000, // 40     95      '_'  UNDERSCORE
-	}
-
-	return codes[index]
+	return s.format.code(index)
 }
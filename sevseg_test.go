@@ -0,0 +1,148 @@
+package sevseg_test
+
+import (
+	"testing"
+
+	"github.com/domi413/sevseg"
+	"github.com/domi413/sevseg/sevsegtest"
+)
+
+// Segment patterns, bit 0 being segment A
+const (
+	blank    = 0b0000000
+	pattern1 = 0b0000110
+	pattern2 = 0b1011011
+	pattern3 = 0b1001111
+	pattern4 = 0b1100110
+	patternE = 0b1111001
+	patternH = 0b1110110
+	patternL = 0b0111000
+	patternO = 0b0111111
+)
+
+// displayTypes are the names of the display types, indexed by whether the
+// display is common cathode.
+var displayTypes = map[bool]string{false: "CommonAnode", true: "CommonCathode"}
+
+// newRecordedDisplay creates a display with 4 digits and 8 segments on the
+// pins of a recorder.
+func newRecordedDisplay(t *testing.T, commonCathode bool) (*sevseg.SevSeg, *sevsegtest.Recorder, sevseg.Config) {
+	t.Helper()
+
+	rec := sevsegtest.NewRecorder()
+	cfg := sevseg.Config{
+		DigitPins:   rec.NewPins("D", 4),
+		SegmentPins: rec.NewPins("S", 8),
+	}
+	if commonCathode {
+		cfg.Hardware = sevseg.CommonCathode
+	}
+
+	display, err := sevseg.NewSevSeg(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return display, rec, cfg
+}
+
+// scanFrame records a full frame and returns the pattern lit on each digit,
+// in the order of the digit pins.
+func scanFrame(display *sevseg.SevSeg, rec *sevsegtest.Recorder, cfg sevseg.Config) []uint8 {
+	rec.Reset()
+	for range cfg.DigitPins {
+		display.Refresh()
+	}
+
+	return rec.Lit(cfg)
+}
+
+// assertFrame fails the test if the frame differs from want, given from the
+// left most digit.
+func assertFrame(t *testing.T, frame []uint8, want ...uint8) {
+	t.Helper()
+
+	for i, pattern := range want {
+		if got := frame[len(frame)-1-i]; got != pattern {
+			t.Fatalf("digit %d from the left: %07b, want %07b (frame %07b)", i, got, pattern, frame)
+		}
+	}
+}
+
+func TestSetNumberFrame(t *testing.T) {
+	for commonCathode, name := range displayTypes {
+		t.Run(name, func(t *testing.T) {
+			display, rec, cfg := newRecordedDisplay(t, commonCathode)
+
+			if err := display.SetNumber(1234); err != nil {
+				t.Fatal(err)
+			}
+			assertFrame(t, scanFrame(display, rec, cfg), pattern1, pattern2, pattern3, pattern4)
+
+			if err := display.SetNumber(-12); err != nil {
+				t.Fatal(err)
+			}
+			assertFrame(t, scanFrame(display, rec, cfg), blank, 0b1000000, pattern1, pattern2)
+		})
+	}
+}
+
+func TestBlankBeforeContent(t *testing.T) {
+	for commonCathode, name := range displayTypes {
+		t.Run(name, func(t *testing.T) {
+			display, rec, cfg := newRecordedDisplay(t, commonCathode)
+
+			for range 3 {
+				assertFrame(t, scanFrame(display, rec, cfg), blank, blank, blank, blank)
+			}
+		})
+	}
+}
+
+func TestScrollText(t *testing.T) {
+	display, rec, cfg := newRecordedDisplay(t, false)
+
+	if err := display.SetText("HELLO"); err != nil {
+		t.Fatal(err)
+	}
+	assertFrame(t, scanFrame(display, rec, cfg), patternH, patternE, patternL, patternL)
+
+	display.ScrollTextLeft()
+	assertFrame(t, scanFrame(display, rec, cfg), patternE, patternL, patternL, patternO)
+
+	display.ScrollTextRight()
+	assertFrame(t, scanFrame(display, rec, cfg), patternH, patternE, patternL, patternL)
+}
+
+func TestPinLevelsBeforeContent(t *testing.T) {
+	for commonCathode, name := range displayTypes {
+		t.Run(name, func(t *testing.T) {
+			display, _, cfg := newRecordedDisplay(t, commonCathode)
+
+			// The digit lines of a common anode display are active high, its
+			// segment lines active low
+			digitOff, segmentOff := commonCathode, !commonCathode
+
+			for i, pin := range cfg.DigitPins {
+				pin := pin.(*sevsegtest.Pin)
+				if !pin.Configured() || pin.IsHigh() != digitOff {
+					t.Errorf("before Refresh: digit pin %d configured %v, high %v, want configured and high %v", i, pin.Configured(), pin.IsHigh(), digitOff)
+				}
+			}
+
+			// Refresh lights the digits in turn, but no segment
+			for calls := range 2*len(cfg.DigitPins) + 1 {
+				if calls > 0 {
+					display.Refresh()
+				}
+
+				for i, pin := range cfg.SegmentPins {
+					pin := pin.(*sevsegtest.Pin)
+					if !pin.Configured() || pin.IsHigh() != segmentOff {
+						t.Errorf("after %d Refresh calls: segment pin %d configured %v, high %v, want configured and high %v", calls, i, pin.Configured(), pin.IsHigh(), segmentOff)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+// Package sevsegtest provides fake pins for testing code using sevseg without
+// hardware. The pins record every High and Low call, from which Lit
+// reconstructs the segments lit on each digit:
+//
+//	rec := sevsegtest.NewRecorder()
+//	cfg := sevseg.Config{
+//		Hardware:    sevseg.CommonCathode,
+//		DigitPins:   rec.NewPins("D", 2),
+//		SegmentPins: rec.NewPins("S", 8),
+//	}
+//	display, _ := sevseg.NewSevSeg(cfg)
+//	display.SetNumber(42)
+//	for range 2 {
+//		display.Refresh()
+//	}
+//	lit := rec.Lit(cfg) // [0b01011011, 0b01100110]
+//
+// The package builds with and without TinyGo, so tests can run on the host
+// with go test.
+package sevsegtest
+
+import (
+	"strconv"
+
+	"github.com/domi413/sevseg"
+)
+
+// Event is a call of High or Low on a recorded pin.
+type Event struct {
+	Seq  uint32 // Position of the call among all calls on the recorder's pins
+	Pin  *Pin
+	High bool
+}
+
+// Recorder records the calls on its pins in the order they happen.
+type Recorder struct {
+	events []Event
+	seq    uint32
+
+	// Levels of the pins before the first recorded call
+	start map[*Pin]bool
+}
+
+// NewRecorder creates a new recorder without pins.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// NewPin creates a pin recording to r. The name is only used for messages.
+func (r *Recorder) NewPin(name string) *Pin {
+	return &Pin{Name: name, recorder: r}
+}
+
+// NewPins creates n pins recording to r, named with the prefix and their
+// index, e.g. "D0", "D1". They can be used directly as the DigitPins or
+// SegmentPins of a sevseg.Config.
+func (r *Recorder) NewPins(prefix string, n int) []sevseg.OutputPin {
+	pins := make([]sevseg.OutputPin, n)
+	for i := range pins {
+		pins[i] = r.NewPin(prefix + strconv.Itoa(i))
+	}
+
+	return pins
+}
+
+// Events returns the recorded calls, the oldest first.
+func (r *Recorder) Events() []Event {
+	return r.events
+}
+
+// Reset forgets the recorded calls. The pins keep their levels, which Lit
+// starts from.
+func (r *Recorder) Reset() {
+	if r.start == nil {
+		r.start = make(map[*Pin]bool)
+	}
+
+	for _, event := range r.events {
+		r.start[event.Pin] = event.Pin.high
+	}
+
+	r.events = r.events[:0]
+}
+
+// Lit replays the recorded calls and returns the segment pattern last lit on
+// each digit, one pattern per pin of cfg.DigitPins in the same order. With
+// the default digit order, this is the format of sevseg.SetSegment. A digit
+// is lit if its digit pin is active, a segment if its segment pin is active,
+// the active levels depending on cfg.Hardware. Digits never lit are 0.
+//
+// Pins in cfg which weren't created by a Recorder are ignored.
+func (r *Recorder) Lit(cfg sevseg.Config) []uint8 {
+	commonAnode := cfg.Hardware == sevseg.CommonAnode
+	levels := make(map[*Pin]bool, len(r.start))
+	for pin, high := range r.start {
+		levels[pin] = high
+	}
+
+	active := func(pin sevseg.OutputPin, activeHigh bool) bool {
+		p, ok := pin.(*Pin)
+
+		return ok && levels[p] == activeHigh
+	}
+
+	lit := make([]uint8, len(cfg.DigitPins))
+	for _, event := range r.events {
+		levels[event.Pin] = event.High
+
+		var pattern uint8
+		for i, pin := range cfg.SegmentPins {
+			if active(pin, !commonAnode) {
+				pattern |= 1 << i
+			}
+		}
+
+		for i, pin := range cfg.DigitPins {
+			if active(pin, commonAnode) {
+				lit[i] = pattern
+			}
+		}
+	}
+
+	return lit
+}
+
+// Pin is a fake sevseg.OutputPin recording every High and Low call. Its level
+// is low until the first call.
+type Pin struct {
+	Name string
+
+	recorder   *Recorder
+	high       bool
+	configured bool
+}
+
+// Configure marks the pin as configured.
+func (p *Pin) Configure(config sevseg.PinConfig) {
+	p.configured = true
+}
+
+// High drives the pin high and records the call.
+func (p *Pin) High() {
+	p.set(true)
+}
+
+// Low drives the pin low and records the call.
+func (p *Pin) Low() {
+	p.set(false)
+}
+
+// IsHigh reports whether the pin is currently high.
+func (p *Pin) IsHigh() bool {
+	return p.high
+}
+
+// Configured reports whether Configure has been called.
+func (p *Pin) Configured() bool {
+	return p.configured
+}
+
+// String returns the name of the pin.
+func (p *Pin) String() string {
+	return p.Name
+}
+
+// set drives the pin and records the call.
+func (p *Pin) set(high bool) {
+	p.high = high
+
+	r := p.recorder
+	r.seq++
+	r.events = append(r.events, Event{Seq: r.seq, Pin: p, High: high})
+}
@@ -0,0 +1,125 @@
+package sevseg
+
+// buttonSettleTime is the time in microseconds the segment lines get to
+// settle after switching to inputs, before the buttons are read.
+const buttonSettleTime = 10
+
+// readablePin is a pin which can also be read as an input, like machine.Pin.
+type readablePin interface {
+	OutputPin
+	Get() bool
+}
+
+// SetSharedPinWindow lets other peripherals use the segment pins between the
+// frames of the display, a common trick on pin-starved boards. Each time
+// Refresh starts a new frame, it turns off all digits, switches the segment
+// pins to inputs (high impedance) and calls window, which may reconfigure and
+// use them freely, e.g. to scan buttons with ScanButtons. Afterwards, the
+// segment pins are restored as outputs. The window should be short, as the
+// display is dark while it's open. Passing nil stops the windows.
+//
+// Returns ErrNotConfigured for displays with a Controller, a shift register,
+// a decoder or tri-color digits, whose segment lines aren't GPIOs of the MCU.
+func (s *SevSeg) SetSharedPinWindow(window func()) error {
+	s.lock()
+	defer s.unlock()
+
+	if !s.directSegmentPins() {
+		return ErrNotConfigured
+	}
+
+	s.shareWindow = window
+	s.windowDue = false
+
+	return nil
+}
+
+// ScanButtons reads buttons wired to the segment lines and returns a bit mask
+// of the pressed ones, bit 0 being the button on segment A. The segment pins
+// are read as inputs with pull-ups, so a button pulls its line low, either
+// to the Config.ButtonCommonPin, which is only driven low during the scan, or
+// directly to ground through a resistor large enough not to disturb the
+// display. The digits are turned off during the scan and the segment pins are
+// restored as outputs afterwards, so it may be called between Refresh calls
+// or from the window of SetSharedPinWindow.
+//
+// Returns ErrNotConfigured if the segment lines aren't GPIOs of the MCU, see
+// SetSharedPinWindow, or a segment pin can't be read.
+func (s *SevSeg) ScanButtons() (uint8, error) {
+	s.lock()
+	defer s.unlock()
+
+	if !s.directSegmentPins() {
+		return 0, ErrNotConfigured
+	}
+
+	for _, pin := range s.segmentPins {
+		if _, ok := pin.(readablePin); !ok {
+			return 0, ErrNotConfigured
+		}
+	}
+
+	s.clearDigitPins()
+	s.releaseSegmentPins(machinePinInputPullup)
+
+	if s.buttonPin != nil {
+		s.buttonPin.Configure(PinConfig{Mode: machinePinOutput})
+		s.buttonPin.Low()
+	}
+
+	s.flushBanks()
+	busyWait(buttonSettleTime)
+
+	var pressed uint8
+	for i, pin := range s.segmentPins {
+		if !pin.(readablePin).Get() {
+			pressed |= 1 << i
+		}
+	}
+
+	if s.buttonPin != nil {
+		s.buttonPin.Configure(PinConfig{Mode: machinePinInput})
+	}
+
+	s.restoreSegmentPins()
+	s.invalidate()
+
+	return pressed, nil
+}
+
+// directSegmentPins reports whether the segment lines are driven by the
+// SegmentPins directly.
+func (s *SevSeg) directSegmentPins() bool {
+	return s.backend == nil && s.shiftRegister == NoShiftRegister && s.decoder == NoDecoder && s.colors == nil
+}
+
+// openSharedPinWindow calls the window of SetSharedPinWindow with the digits
+// off and the segment pins released.
+func (s *SevSeg) openSharedPinWindow() {
+	s.windowDue = false
+
+	s.clearDigitPins()
+	s.releaseSegmentPins(machinePinInput)
+	s.flushBanks()
+
+	s.shareWindow()
+
+	s.restoreSegmentPins()
+}
+
+// releaseSegmentPins switches the segment pins to inputs with the given mode.
+func (s *SevSeg) releaseSegmentPins(mode machinePinMode) {
+	for _, pin := range s.segmentPins {
+		pin.Configure(PinConfig{Mode: mode})
+	}
+}
+
+// restoreSegmentPins switches the segment pins back to outputs, turned off.
+func (s *SevSeg) restoreSegmentPins() {
+	for _, pin := range s.segmentPins {
+		pin.Configure(PinConfig{Mode: machinePinOutput})
+		s.setSegmentPin(pin, false)
+	}
+
+	s.flushBanks()
+}
@@ -0,0 +1,159 @@
+package sevseg
+
+import "time"
+
+// stringer is fmt.Stringer, declared here so the package doesn't pull in fmt.
+type stringer interface {
+	String() string
+}
+
+// Show displays a value of any of the common types with sensible defaults,
+// a convenience for quick prototypes and teaching material:
+//
+//   - Integers are shown like SetNumber.
+//   - Floats are shown with as many decimal places as fit, or rounded to an
+//     integer on displays without a decimal point pin.
+//   - Strings and values implementing fmt.Stringer are shown like SetText.
+//   - A time.Duration is shown like SetSexagesimal, in whole seconds.
+//   - A time.Time is shown as the time of day in the H.MM.SS style, without
+//     the seconds if they don't fit, or as HHMM without a decimal point pin.
+//
+// Returns ErrInvalidArgument for other types and negative durations, or the
+// error of the method showing the value, e.g. ErrTooManyDigits.
+func (s *SevSeg) Show(v any) error {
+	s.lock()
+	defer s.unlock()
+
+	switch v := v.(type) {
+	case int:
+		return s.showInteger(int64(v))
+	case int8:
+		return s.showInteger(int64(v))
+	case int16:
+		return s.showInteger(int64(v))
+	case int32:
+		return s.showInteger(int64(v))
+	case int64:
+		return s.showInteger(v)
+	case uint:
+		return s.showUnsigned(uint64(v))
+	case uint8:
+		return s.showInteger(int64(v))
+	case uint16:
+		return s.showInteger(int64(v))
+	case uint32:
+		return s.showInteger(int64(v))
+	case uint64:
+		return s.showUnsigned(v)
+	case float32:
+		return s.showFloat(float64(v))
+	case float64:
+		return s.showFloat(v)
+	case string:
+		return s.SetText(v)
+	case time.Duration:
+		if v < 0 {
+			return ErrInvalidArgument
+		}
+
+		seconds := v / time.Second
+		if seconds > 1<<32-1 {
+			return ErrTooManyDigits
+		}
+
+		return s.SetSexagesimal(uint32(seconds))
+	case time.Time:
+		return s.showTimeOfDay(v)
+	case stringer:
+		return s.SetText(v.String())
+	}
+
+	return ErrInvalidArgument
+}
+
+// showInteger shows an integer like SetNumber.
+func (s *SevSeg) showInteger(number int64) error {
+	if number < -1<<31 || number > 1<<31-1 {
+		return ErrTooManyDigits // No display is that wide
+	}
+
+	return s.SetNumber(int32(number))
+}
+
+// showUnsigned is showInteger for unsigned integers exceeding int64.
+func (s *SevSeg) showUnsigned(number uint64) error {
+	if number > 1<<31-1 {
+		return ErrTooManyDigits
+	}
+
+	return s.SetNumber(int32(number))
+}
+
+// showFloat shows a float with as many decimal places as fit, or rounded to
+// an integer without a decimal point pin.
+func (s *SevSeg) showFloat(number float64) error {
+	magnitude := number
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+
+	if s.HasDecimalPoint() {
+		// At least one digit is left for the integer part
+		scale := 10.0
+		for places := uint8(1); places < uint8(len(s.digitPins)); places++ {
+			scale *= 10
+		}
+
+		for places := uint8(len(s.digitPins)) - 1; places > 0; places-- {
+			scale /= 10
+
+			// SetNumberFloat scales the number to an int32
+			if magnitude*scale >= 1<<31 {
+				continue
+			}
+
+			if s.SetNumberFloat(float32(number), places) == nil {
+				return nil
+			}
+		}
+	}
+
+	if magnitude >= 1<<31 {
+		return ErrTooManyDigits
+	}
+
+	if number < 0 {
+		return s.SetNumber(int32(number - 0.5))
+	}
+
+	return s.SetNumber(int32(number + 0.5))
+}
+
+// showTimeOfDay shows the time of day as H.MM.SS or H.MM, or as HHMM without
+// a decimal point pin.
+func (s *SevSeg) showTimeOfDay(t time.Time) error {
+	hour, minute, second := t.Clock()
+
+	if !s.HasDecimalPoint() {
+		if err := s.format.Digits(s.updatedDisplay, uint32(hour*100+minute), false, 10, 4); err != nil {
+			return err
+		}
+
+		s.commitNumber(ContentNumber, false)
+
+		return nil
+	}
+
+	err := s.format.sexagesimal(s.updatedDisplay, []uint32{uint32(second), uint32(minute), uint32(hour)}, 3, 1, false)
+	if err != nil {
+		err = s.format.sexagesimal(s.updatedDisplay, []uint32{uint32(minute), uint32(hour)}, 2, 1, false)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	s.commitNumber(ContentNumber, false)
+
+	return nil
+}
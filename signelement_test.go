@@ -0,0 +1,56 @@
+//go:build !tinygo
+
+package sevseg
+
+import "testing"
+
+func TestSignElement(t *testing.T) {
+	const minus = 0b1000000
+
+	s, err := NewSevSeg(Config{
+		DigitPins:         SimulatedPins(4),
+		SegmentPins:       SimulatedPins(8),
+		SignDigitPin:      machinePin(20),
+		SignMinusSegments: minus,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name   string
+		set    func() error
+		digits string
+		lit    bool
+	}{
+		{"SetNumber(-5)", func() error { return s.SetNumber(-5) }, "   5", true},
+		{"UpdateLowestDigits(1, 7)", func() error { return s.UpdateLowestDigits(1, 7) }, "   7", true},
+		{"SetHex(0x1F)", func() error { return s.SetHex(0x1F) }, "  1F", false},
+		{"SetNumber(-1234)", func() error { return s.SetNumber(-1234) }, "1234", true},
+		{"SetTemperature(-2, 0)", func() error { return s.SetTemperature(-2, 0) }, "  2*", true},
+		{"SetNumber(12)", func() error { return s.SetNumber(12) }, "  12", false},
+		{"SetNumberWithDecimal(-12, 1)", func() error { return s.SetNumberWithDecimal(-12, 1) }, "  12", true},
+		{"SetText(\"AB\")", func() error { return s.SetText("AB") }, "AB  ", false},
+	}
+
+	for _, step := range steps {
+		if err := step.set(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		want, err := s.format.Patterns(step.digits)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, pattern := range want {
+			if got := s.updatedDisplay[len(want)-1-i] &^ 0b10000000; got != pattern { // Without the decimal point
+				t.Errorf("%s: digit %d from the left %07b, want %07b", step.name, i, got, pattern)
+			}
+		}
+
+		if lit := s.auxDisplay[len(s.auxDisplay)-1] == minus; lit != step.lit {
+			t.Errorf("%s: sign element lit %t, want %t", step.name, lit, step.lit)
+		}
+	}
+}
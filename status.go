@@ -0,0 +1,56 @@
+package sevseg
+
+// Status is a common status shown with ShowStatus.
+type Status uint8
+
+// The statuses of ShowStatus, so products share consistent conventions:
+//
+//	StatusError      Err   A generic error
+//	StatusOverload   OL    The value exceeds the measuring range
+//	StatusLow        LO    The value is below the valid range
+//	StatusHigh       HI    The value is above the valid range
+//	StatusNoValue    ---   No value is available (yet)
+//	StatusBattery    bAt   The battery is low
+const (
+	StatusError Status = iota
+	StatusOverload
+	StatusLow
+	StatusHigh
+	StatusNoValue
+	StatusBattery
+)
+
+// statusTexts holds the texts of the statuses, indexed by Status.
+var statusTexts = [...]string{
+	StatusError:    "Err",
+	StatusOverload: "OL",
+	StatusLow:      "LO",
+	StatusHigh:     "HI",
+	StatusNoValue:  "---",
+	StatusBattery:  "bAt",
+}
+
+// String returns the text shown for the status, or "" for an unknown one.
+func (st Status) String() string {
+	if int(st) >= len(statusTexts) {
+		return ""
+	}
+
+	return statusTexts[st]
+}
+
+// ShowStatus shows a common status like "Err" or "OL" as text, see Status.
+//
+// Returns ErrInvalidArgument for an unknown status. On a display narrower
+// than the text, it can be scrolled like any text set with SetText.
+func (s *SevSeg) ShowStatus(status Status) error {
+	s.lock()
+	defer s.unlock()
+
+	text := status.String()
+	if text == "" {
+		return ErrInvalidArgument
+	}
+
+	return s.SetText(text)
+}
@@ -0,0 +1,49 @@
+package sevseg
+
+import "io"
+
+// terminal is a Driver rendering the frames as ASCII art.
+type terminal struct {
+	w     io.Writer
+	drawn bool
+}
+
+// Terminal returns a Driver which renders every frame to w as ASCII-art
+// digits, the same way PrintFont shows the characters. Together with
+// SimulatedPins, this allows prototyping text, scrolling and animations on a
+// computer before flashing the hardware:
+//
+//	display, _ := sevseg.NewSevSeg(sevseg.Config{
+//		DigitPins:   sevseg.SimulatedPins(4),
+//		SegmentPins: sevseg.SimulatedPins(8),
+//	})
+//	display.SetMirror(sevseg.Terminal(os.Stdout))
+//	display.SetText("HELLO")
+//
+// Each frame overwrites the previous one using ANSI escape sequences, so the
+// display appears animated in a terminal. The driver also works on the
+// microcontroller, e.g. with machine.Serial and a terminal program.
+func Terminal(w io.Writer) Driver {
+	return &terminal{w: w}
+}
+
+// WriteFrame renders the frame, the left most digit first.
+func (t *terminal) WriteFrame(frame []uint8) {
+	var lines [3][]byte
+	for i := len(frame) - 1; i >= 0; i-- {
+		appendSegmentArt(lines[:], frame[i])
+	}
+
+	var out []byte
+	if t.drawn {
+		out = append(out, "\x1b[3A"...) // Back to the first line of the previous frame
+	}
+
+	for _, line := range lines {
+		out = append(out, line...)
+		out = append(out, "\x1b[K\n"...) // Clear the rest of the line
+	}
+
+	t.drawn = true
+	_, _ = t.w.Write(out) // A Driver has no way to report errors
+}
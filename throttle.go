@@ -0,0 +1,38 @@
+package sevseg
+
+import "time"
+
+// SetRefreshThrottle limits how often Refresh does any work while the content
+// and brightness are static, to save CPU time and power when Refresh is
+// called from a busy loop. Calls within interval of the last performed
+// refresh return immediately and keep the current digit lit.
+//
+// Choose the interval so a full frame stays fast enough to avoid flicker,
+// e.g. 2.5ms for a 4-digit display (100 frames per second). With
+// SoftwarePWM and a brightness below 100, a frame takes PWMSteps times longer.
+// After the content or brightness changed, Refresh runs unthrottled until a
+// full frame has been shown. Periods given in ticks, e.g. for AlternateWith,
+// only count performed refreshes. An interval of 0 disables the throttling.
+func (s *SevSeg) SetRefreshThrottle(interval time.Duration) {
+	s.lock()
+	defer s.unlock()
+
+	s.throttleInterval = interval
+	s.dirty = true
+}
+
+// throttled reports whether the current Refresh call should be skipped.
+func (s *SevSeg) throttled() bool {
+	if s.throttleInterval == 0 {
+		return false
+	}
+
+	now := time.Now()
+	if !s.dirty && now.Sub(s.lastRefresh) < s.throttleInterval {
+		return true
+	}
+
+	s.lastRefresh = now
+
+	return false
+}
@@ -0,0 +1,132 @@
+package sevseg
+
+import (
+	"bytes"
+	"time"
+)
+
+const (
+	tm1637DataCommand    = 0x40 // Write data with automatic address increment
+	tm1637AddressCommand = 0xC0 // Start at the first digit
+	tm1637DisplayOff     = 0x80
+	tm1637DisplayOn      = 0x88 // Or'ed with the brightness level (0-7)
+
+	// tm1637Delay is the half period of the clock, well below the maximum
+	// clock rate of the TM1637 with the weak pull-ups of common modules.
+	tm1637Delay = 5 * time.Microsecond
+)
+
+// tm1637 is the backend for the TM1637 controller. Its 2-wire protocol
+// resembles I2C without addresses, the bytes are sent LSB first. Frames and
+// brightness are only sent if they changed.
+type tm1637 struct {
+	clk     machinePin
+	dio     machinePin
+	frame   []uint8 // Last frame sent
+	control uint8   // Last display control command sent, 0 if none
+	sent    bool
+}
+
+// newTM1637 creates a TM1637 backend driving the given number of digits.
+func newTM1637(clk, dio machinePin, digits uint8) *tm1637 {
+	t := &tm1637{
+		clk:   clk,
+		dio:   dio,
+		frame: make([]uint8, digits),
+	}
+
+	// Both lines idle high
+	t.setLine(t.clk, true)
+	t.setLine(t.dio, true)
+
+	return t
+}
+
+// writeFrame sends a frame to the display memory. The first address is the
+// left most digit, the segment bits match the patterns of this package.
+func (t *tm1637) writeFrame(frame []uint8) {
+	if t.sent && bytes.Equal(frame, t.frame) {
+		return
+	}
+
+	copy(t.frame, frame)
+	t.sent = true
+
+	t.start()
+	t.writeByte(tm1637DataCommand)
+	t.stop()
+
+	t.start()
+	t.writeByte(tm1637AddressCommand)
+	for i := len(frame) - 1; i >= 0; i-- {
+		t.writeByte(frame[i])
+	}
+	t.stop()
+}
+
+// setBrightness maps the brightness to the 8 levels of the controller.
+func (t *tm1637) setBrightness(brightness uint8) {
+	control := uint8(tm1637DisplayOff)
+	if brightness > 0 {
+		control = tm1637DisplayOn | uint8((uint16(brightness)-1)*8/100)
+	}
+
+	if control == t.control {
+		return
+	}
+
+	t.control = control
+
+	t.start()
+	t.writeByte(control)
+	t.stop()
+}
+
+// start signals the start of a command: DIO falls while CLK is high.
+func (t *tm1637) start() {
+	t.setLine(t.dio, false)
+	time.Sleep(tm1637Delay)
+}
+
+// stop signals the end of a command: DIO rises while CLK is high.
+func (t *tm1637) stop() {
+	t.setLine(t.clk, false)
+	t.setLine(t.dio, false)
+	time.Sleep(tm1637Delay)
+	t.setLine(t.clk, true)
+	time.Sleep(tm1637Delay)
+	t.setLine(t.dio, true)
+	time.Sleep(tm1637Delay)
+}
+
+// writeByte sends a byte LSB first and skips the acknowledge of the
+// controller, there's nothing to do about a missing one.
+func (t *tm1637) writeByte(b uint8) {
+	for i := range 8 {
+		t.setLine(t.clk, false)
+		t.setLine(t.dio, b&(1<<i) != 0)
+		time.Sleep(tm1637Delay)
+		t.setLine(t.clk, true)
+		time.Sleep(tm1637Delay)
+	}
+
+	// Release DIO for the acknowledge bit
+	t.setLine(t.clk, false)
+	t.setLine(t.dio, true)
+	time.Sleep(tm1637Delay)
+	t.setLine(t.clk, true)
+	time.Sleep(tm1637Delay)
+	t.setLine(t.clk, false)
+}
+
+// setLine drives a line low or releases it to be pulled high by the pull-up
+// of the module, as the lines are open drain.
+func (t *tm1637) setLine(pin machinePin, high bool) {
+	if high {
+		pin.Configure(PinConfig{Mode: machinePinInput})
+		return
+	}
+
+	pin.Configure(PinConfig{Mode: machinePinOutput})
+	pin.Low()
+}
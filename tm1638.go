@@ -0,0 +1,155 @@
+package sevseg
+
+import "time"
+
+const (
+	tm1638DataCommand    = 0x40 // Write data with automatic address increment
+	tm1638ReadKeys       = 0x42
+	tm1638AddressCommand = 0xC0 // Start at the first digit
+	tm1638DisplayOff     = 0x80
+	tm1638DisplayOn      = 0x88 // Or'ed with the brightness level (0-7)
+
+	// tm1638Delay is the half period of the clock, the TM1638 is clocked
+	// with at most 1MHz.
+	tm1638Delay = time.Microsecond
+)
+
+// tm1638 is the backend for the TM1638 controller of LED&KEY boards. Commands
+// are framed by the strobe (STB) line and sent LSB first. The display memory
+// alternates between the digits (even addresses) and the LEDs (odd
+// addresses), it's only sent if it changed.
+type tm1638 struct {
+	stb     machinePin
+	clk     machinePin
+	dio     machinePin
+	memory  [16]uint8
+	sent    [16]uint8 // Display memory last sent
+	control uint8     // Last display control command sent, 0 if none
+	synced  bool
+}
+
+// newTM1638 creates a TM1638 backend.
+func newTM1638(stb, clk, dio machinePin) *tm1638 {
+	t := &tm1638{
+		stb: stb,
+		clk: clk,
+		dio: dio,
+	}
+
+	for _, pin := range []machinePin{stb, clk, dio} {
+		pin.Configure(PinConfig{Mode: machinePinOutput})
+		pin.High()
+	}
+
+	return t
+}
+
+// writeFrame puts the frame into the display memory, the left most digit at
+// the first address.
+func (t *tm1638) writeFrame(frame []uint8) {
+	for i, pattern := range frame {
+		t.memory[2*(len(frame)-1-i)] = pattern
+	}
+
+	t.flush()
+}
+
+// writeAux puts the LEDs into the display memory, bit i of the first group
+// being the LED below the i-th digit from the left.
+func (t *tm1638) writeAux(aux []uint8) {
+	for i := range 8 {
+		t.memory[2*i+1] = (aux[0] >> i) & 1
+	}
+
+	t.flush()
+}
+
+// flush sends the display memory if it changed.
+func (t *tm1638) flush() {
+	if t.synced && t.memory == t.sent {
+		return
+	}
+
+	t.sent = t.memory
+	t.synced = true
+
+	t.command(tm1638DataCommand)
+
+	t.stb.Low()
+	t.writeByte(tm1638AddressCommand)
+	for _, b := range t.memory {
+		t.writeByte(b)
+	}
+	t.stb.High()
+}
+
+// setBrightness maps the brightness to the 8 levels of the controller.
+func (t *tm1638) setBrightness(brightness uint8) {
+	control := uint8(tm1638DisplayOff)
+	if brightness > 0 {
+		control = tm1638DisplayOn | uint8((uint16(brightness)-1)*8/100)
+	}
+
+	if control == t.control {
+		return
+	}
+
+	t.control = control
+	t.command(control)
+}
+
+// readKeys reads the key matrix, bit i being set if the i-th key from the
+// left is pressed.
+func (t *tm1638) readKeys() uint8 {
+	t.stb.Low()
+	t.writeByte(tm1638ReadKeys)
+
+	// The controller drives DIO while the keys are read
+	t.dio.Configure(PinConfig{Mode: machinePinInputPullup})
+	time.Sleep(tm1638Delay)
+
+	// The keys are spread over the bits 0 and 4 of the four bytes read
+	keys := uint8(0)
+	for i := range 4 {
+		keys |= t.readByte() << i
+	}
+
+	t.dio.Configure(PinConfig{Mode: machinePinOutput})
+	t.stb.High()
+
+	return keys
+}
+
+// command sends a single byte command to the controller.
+func (t *tm1638) command(command uint8) {
+	t.stb.Low()
+	t.writeByte(command)
+	t.stb.High()
+}
+
+// writeByte sends a byte LSB first, DIO is sampled on the rising clock edge.
+func (t *tm1638) writeByte(b uint8) {
+	for i := range 8 {
+		t.clk.Low()
+		t.dio.Set(b&(1<<i) != 0)
+		time.Sleep(tm1638Delay)
+		t.clk.High()
+		time.Sleep(tm1638Delay)
+	}
+}
+
+// readByte reads a byte LSB first.
+func (t *tm1638) readByte() uint8 {
+	b := uint8(0)
+	for i := range 8 {
+		t.clk.Low()
+		time.Sleep(tm1638Delay)
+		t.clk.High()
+		if t.dio.Get() {
+			b |= 1 << i
+		}
+		time.Sleep(tm1638Delay)
+	}
+
+	return b
+}
@@ -0,0 +1,53 @@
+package sevseg
+
+// ShowWarning alternates whole frames between the current content and a warning
+// message, e.g. "HI" when a threshold is exceeded. The display flips every
+// periodTicks ticks of the effects clock, i.e. full frames of Refresh, so the
+// value remains readable.
+//
+// The warning takes precedence over AlternateWith. The current content can
+// still be updated while the warning is shown.
+func (s *SevSeg) ShowWarning(text string, periodTicks uint16) error {
+	s.lock()
+	defer s.unlock()
+
+	if periodTicks == 0 {
+		return ErrInvalidArgument
+	}
+
+	warning := make([]uint8, len(s.digitPins))
+	if err := s.format.Text(warning, text); err != nil {
+		return err
+	}
+
+	s.showWarning = false // Replaced while it isn't shown, see ClearWarning
+	s.warningContent = warning
+	s.warningPeriod = periodTicks
+	s.warningNext = s.deadline(periodTicks)
+	s.showWarning = true
+
+	return nil
+}
+
+// ClearWarning stops showing the warning message.
+func (s *SevSeg) ClearWarning() {
+	s.lock()
+	defer s.unlock()
+
+	// Refresh may run from a timer interrupt between the two stores, the
+	// content must stay until the warning isn't shown anymore
+	s.showWarning = false
+	s.warningContent = nil
+}
+
+// advanceWarning advances the warning alternation by one tick of the effects
+// clock.
+func (s *SevSeg) advanceWarning() {
+	if s.warningContent == nil {
+		return
+	}
+
+	if s.expired(&s.warningNext, s.warningPeriod) {
+		s.showWarning = !s.showWarning
+	}
+}